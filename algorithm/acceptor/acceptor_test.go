@@ -0,0 +1,174 @@
+package acceptor
+
+import (
+	"testing"
+
+	"gsprit/problem/solution"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSolution(cost float64) *solution.VehicleRoutingProblemSolution {
+	return solution.NewVehicleRoutingProblemSolution(nil, cost)
+}
+
+func TestGreedyAcceptance_ShouldReplaceWorstSolutionWhenMemoryIsFull(t *testing.T) {
+	a := NewGreedyAcceptance(2)
+	solutions := []*solution.VehicleRoutingProblemSolution{}
+
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(10.)))
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(20.)))
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(25.)))
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(15.)))
+
+	assert.Len(t, solutions, 2)
+	assert.Equal(t, 10., solutions[0].Cost())
+	assert.Equal(t, 15., solutions[1].Cost())
+}
+
+func TestGreedyAcceptance_AcceptSolution_ShouldReplaceSolutionsInPlaceOnly(t *testing.T) {
+	a := NewGreedyAcceptance(2)
+	solutions := []*solution.VehicleRoutingProblemSolution{newSolution(10.), newSolution(20.)}
+
+	assert.True(t, a.AcceptSolution(solutions, newSolution(15.)))
+	assert.False(t, a.AcceptSolution(solutions, newSolution(25.)))
+
+	assert.Equal(t, 10., solutions[0].Cost())
+	assert.Equal(t, 15., solutions[1].Cost())
+
+	// the pool is taken as full although the memory is larger, thus a worse solution is not accepted
+	pool := solutions[:1]
+	assert.False(t, a.AcceptSolution(pool, newSolution(30.)))
+	assert.True(t, a.AcceptSolution(pool, newSolution(5.)))
+	assert.Equal(t, 5., solutions[0].Cost())
+	assert.Equal(t, 15., solutions[1].Cost())
+	assert.False(t, a.AcceptSolution(nil, newSolution(1.)))
+}
+
+func TestAcceptors_AcceptSolution_ShouldNeverAcceptWithoutStoring(t *testing.T) {
+	for _, a := range []SolutionAcceptor{NewGreedyAcceptance(3), NewSchrimpfAcceptance(3, 0.3), NewGreatDelugeAcceptance(3, 1.1, 1.),
+		NewLateAcceptanceHillClimbing(3, 2), NewRecordToRecordTravelAcceptance(3, 0.1),
+		NewSimulatedAnnealingAcceptance(3, 1., 1., LinearCooling)} {
+		solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+		newSol := newSolution(200.)
+
+		accepted := a.AcceptSolution(solutions, newSol)
+
+		assert.Equal(t, accepted, solutions[0] == newSol, "%v", a)
+	}
+}
+
+type algorithmStub struct {
+	maxIterations int
+}
+
+func (a *algorithmStub) SearchSolutions() ([]*solution.VehicleRoutingProblemSolution, error) {
+	return nil, nil
+}
+
+func (a *algorithmStub) MaxIterations() int {
+	return a.maxIterations
+}
+
+func TestSchrimpfAcceptance_ThresholdShouldHalveEveryAlphaShareOfIterations(t *testing.T) {
+	a := NewSchrimpfAcceptance(1, .25)
+	a.SetInitialThreshold(80.)
+	a.InformAlgorithmStarts(nil, &algorithmStub{maxIterations: 100}, nil)
+
+	a.InformIterationStarts(25, nil, nil)
+	assert.InDelta(t, 40., a.Threshold(), 1e-9)
+	a.InformIterationStarts(50, nil, nil)
+	assert.InDelta(t, 20., a.Threshold(), 1e-9)
+
+	solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(115.)))
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(140.)))
+	assert.Equal(t, 115., solutions[0].Cost())
+}
+
+func TestSchrimpfAcceptance_ShouldDeriveInitialThresholdFromInitialSolutions(t *testing.T) {
+	a := NewSchrimpfAcceptance(1, .1)
+	a.InformAlgorithmStarts(nil, &algorithmStub{maxIterations: 10},
+		[]*solution.VehicleRoutingProblemSolution{newSolution(300.), newSolution(200.)})
+
+	assert.InDelta(t, 20., a.Threshold(), 1e-9)
+}
+
+func TestSimulatedAnnealingAcceptance_ShouldCoolDownToEndTemperature(t *testing.T) {
+	a := NewSimulatedAnnealingAcceptance(1, 100., 1., ExponentialCooling)
+	a.InformAlgorithmStarts(nil, &algorithmStub{maxIterations: 10}, nil)
+	assert.Equal(t, 100., a.Temperature())
+
+	a.InformIterationStarts(5, nil, nil)
+	assert.InDelta(t, 10., a.Temperature(), 1e-9)
+	a.InformIterationStarts(10, nil, nil)
+	assert.InDelta(t, 1., a.Temperature(), 1e-9)
+
+	l := NewSimulatedAnnealingAcceptance(1, 100., 1., LinearCooling)
+	l.SetMaxIterations(10)
+	l.InformIterationStarts(5, nil, nil)
+	assert.InDelta(t, 50.5, l.Temperature(), 1e-9)
+}
+
+func TestSimulatedAnnealingAcceptance_ShouldAcceptWorseSolutionsLessOftenWhenCold(t *testing.T) {
+	acceptWorse := func(temperature float64) int {
+		a := NewSimulatedAnnealingAcceptance(1, temperature, temperature, LinearCooling)
+		n := 0
+		for i := 0; i < 1000; i++ {
+			solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+			if a.AcceptSolutionIntoPool(&solutions, newSolution(110.)) {
+				n++
+			}
+		}
+		return n
+	}
+
+	hot, cold := acceptWorse(100.), acceptWorse(1.)
+	assert.Greater(t, hot, 800)
+	assert.Less(t, cold, 10)
+	assert.Less(t, cold, hot)
+}
+
+func TestRecordToRecordTravelAcceptance_ShouldAcceptSolutionsCloseToRecord(t *testing.T) {
+	a := NewRecordToRecordTravelAcceptance(1, .1)
+	solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+	a.InformAlgorithmStarts(nil, &algorithmStub{}, solutions)
+
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(108.)))
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(111.)))
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(90.)))
+	assert.Equal(t, 90., a.Record())
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(100.)))
+	assert.Equal(t, 90., solutions[0].Cost())
+}
+
+func TestLateAcceptanceHillClimbing_ShouldAcceptSolutionsNotWorseThanHistory(t *testing.T) {
+	a := NewLateAcceptanceHillClimbing(1, 2)
+	solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+	a.InformAlgorithmStarts(nil, &algorithmStub{}, solutions)
+
+	// history [100 100]: a worse solution is rejected, the current costs are recorded
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(105.)))
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(90.)))
+	// history [100 90]: 95 is worse than the current 90 but not worse than the costs recorded two evaluations ago
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(95.)))
+	// history [95 90]
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(96.)))
+	assert.Equal(t, 95., solutions[0].Cost())
+}
+
+func TestGreatDelugeAcceptance_WaterLevelShouldDecreaseLinearly(t *testing.T) {
+	a := NewGreatDelugeAcceptance(1, 1.2, .8)
+	solutions := []*solution.VehicleRoutingProblemSolution{newSolution(100.)}
+	a.InformAlgorithmStarts(nil, &algorithmStub{maxIterations: 10}, solutions)
+	assert.InDelta(t, 120., a.Level(), 1e-9)
+
+	a.InformIterationStarts(5, nil, nil)
+	assert.InDelta(t, 100., a.Level(), 1e-9)
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(100.)))
+
+	a.InformIterationStarts(10, nil, nil)
+	assert.InDelta(t, 80., a.Level(), 1e-9)
+	assert.False(t, a.AcceptSolutionIntoPool(&solutions, newSolution(101.)))
+	assert.True(t, a.AcceptSolutionIntoPool(&solutions, newSolution(99.)))
+}
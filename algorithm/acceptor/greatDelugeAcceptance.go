@@ -0,0 +1,88 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+)
+
+var (
+	_ SolutionAcceptor     = (*GreatDelugeAcceptance)(nil)
+	_ SolutionPoolAcceptor = (*GreatDelugeAcceptance)(nil)
+)
+
+// GreatDelugeAcceptance is the great deluge algorithm of Dueck (1993). A new solution is accepted if its costs do
+// not exceed the water level or if it is better than the worst solution in memory, which it then replaces.
+//
+// The water level is relative to the costs of the best initial solution: it starts at initialLevelFactor times
+// these costs and decreases linearly with the iterations to finalLevelFactor times these costs.
+type GreatDelugeAcceptance struct {
+	iterationProgress
+	solutionMemory     int
+	initialLevelFactor float64
+	finalLevelFactor   float64
+	initialCosts       float64
+	level              float64
+}
+
+func NewGreatDelugeAcceptance(solutionMemory int, initialLevelFactor, finalLevelFactor float64) *GreatDelugeAcceptance {
+	checkSolutionMemory(solutionMemory)
+	if finalLevelFactor < 0. || initialLevelFactor < finalLevelFactor {
+		panic("level factors must not be negative and the initial factor must not be lower than the final factor")
+	}
+	return &GreatDelugeAcceptance{
+		solutionMemory:     solutionMemory,
+		initialLevelFactor: initialLevelFactor,
+		finalLevelFactor:   finalLevelFactor,
+	}
+}
+
+// Level returns the water level of the current iteration.
+func (a *GreatDelugeAcceptance) Level() float64 {
+	return a.level
+}
+
+func (a *GreatDelugeAcceptance) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformAlgorithmStarts(problem, algorithm, solutions)
+	a.initialCosts = 0.
+	if len(solutions) > 0 {
+		a.initialCosts = solution.BestOf(solutions).Cost()
+	}
+	a.level = a.initialLevelFactor * a.initialCosts
+}
+
+func (a *GreatDelugeAcceptance) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformIterationStarts(i, problem, solutions)
+	factor := a.initialLevelFactor - (a.initialLevelFactor-a.finalLevelFactor)*a.progress()
+	a.level = factor * a.initialCosts
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *GreatDelugeAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *GreatDelugeAcceptance) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *GreatDelugeAcceptance) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	if len(*solutions) < solutionMemory {
+		*solutions = append(*solutions, newSolution)
+		return true
+	}
+	worst := worstIndex(*solutions)
+	if newSolution.Cost() <= a.level || newSolution.Cost() < (*solutions)[worst].Cost() {
+		(*solutions)[worst] = newSolution
+		return true
+	}
+	return false
+}
+
+func (a *GreatDelugeAcceptance) String() string {
+	return fmt.Sprintf("[name=greatDelugeAcceptance][solutionMemory=%d][initialLevelFactor=%.2f][finalLevelFactor=%.2f]",
+		a.solutionMemory, a.initialLevelFactor, a.finalLevelFactor)
+}
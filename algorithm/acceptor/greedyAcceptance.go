@@ -0,0 +1,43 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+)
+
+var (
+	_ SolutionAcceptor     = (*GreedyAcceptance)(nil)
+	_ SolutionPoolAcceptor = (*GreedyAcceptance)(nil)
+)
+
+// GreedyAcceptance keeps the best solutions found so far. As long as the memory is not full, every new solution
+// is accepted; afterwards a new solution replaces the worst solution in memory if it is better.
+type GreedyAcceptance struct {
+	solutionMemory int
+}
+
+func NewGreedyAcceptance(solutionMemory int) *GreedyAcceptance {
+	checkSolutionMemory(solutionMemory)
+	return &GreedyAcceptance{
+		solutionMemory: solutionMemory,
+	}
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *GreedyAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *GreedyAcceptance) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *GreedyAcceptance) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	return acceptWithThreshold(solutions, newSolution, solutionMemory, 0.)
+}
+
+func (a *GreedyAcceptance) String() string {
+	return fmt.Sprintf("[name=greedyAcceptance][solutionMemory=%d]", a.solutionMemory)
+}
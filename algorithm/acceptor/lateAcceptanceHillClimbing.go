@@ -0,0 +1,87 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+)
+
+var (
+	_ SolutionAcceptor     = (*LateAcceptanceHillClimbing)(nil)
+	_ SolutionPoolAcceptor = (*LateAcceptanceHillClimbing)(nil)
+)
+
+// LateAcceptanceHillClimbing is the late acceptance hill climbing of Burke and Bykov (2017). It keeps a circular
+// history of the costs of the current solution in the last historyLength evaluations. A new solution is accepted
+// if it is not worse than the current solution, i.e. the worst solution in memory, or than the costs recorded
+// historyLength evaluations ago. The accepted solution replaces the worst one.
+type LateAcceptanceHillClimbing struct {
+	solutionMemory int
+	history        []float64
+	initialized    bool
+	evaluations    int
+}
+
+func NewLateAcceptanceHillClimbing(solutionMemory, historyLength int) *LateAcceptanceHillClimbing {
+	checkSolutionMemory(solutionMemory)
+	if historyLength < 1 {
+		panic("history length must be at least 1")
+	}
+	return &LateAcceptanceHillClimbing{
+		solutionMemory: solutionMemory,
+		history:        make([]float64, historyLength),
+	}
+}
+
+// InformAlgorithmStarts fills the history with the costs of the best initial solution.
+func (a *LateAcceptanceHillClimbing) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	a.evaluations = 0
+	a.initialized = false
+	if len(solutions) > 0 {
+		a.initHistory(solution.BestOf(solutions).Cost())
+	}
+}
+
+func (a *LateAcceptanceHillClimbing) initHistory(costs float64) {
+	for i := range a.history {
+		a.history[i] = costs
+	}
+	a.initialized = true
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *LateAcceptanceHillClimbing) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *LateAcceptanceHillClimbing) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *LateAcceptanceHillClimbing) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	if len(*solutions) < solutionMemory {
+		*solutions = append(*solutions, newSolution)
+		return true
+	}
+	worst := worstIndex(*solutions)
+	current := (*solutions)[worst].Cost()
+	if !a.initialized {
+		a.initHistory(current)
+	}
+	slot := a.evaluations % len(a.history)
+	a.evaluations++
+	accepted := newSolution.Cost() <= current || newSolution.Cost() <= a.history[slot]
+	if accepted {
+		(*solutions)[worst] = newSolution
+		current = newSolution.Cost()
+	}
+	a.history[slot] = current
+	return accepted
+}
+
+func (a *LateAcceptanceHillClimbing) String() string {
+	return fmt.Sprintf("[name=lateAcceptanceHillClimbing][solutionMemory=%d][historyLength=%d]", a.solutionMemory, len(a.history))
+}
@@ -0,0 +1,76 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var (
+	_ SolutionAcceptor     = (*RecordToRecordTravelAcceptance)(nil)
+	_ SolutionPoolAcceptor = (*RecordToRecordTravelAcceptance)(nil)
+)
+
+// RecordToRecordTravelAcceptance is the record-to-record travel of Dueck (1993). A new solution replaces the worst
+// solution in memory if its costs do not exceed the costs of the best solution found so far, the record, by more
+// than the given deviation, e.g. a deviation of .01 allows solutions up to one percent worse than the record.
+type RecordToRecordTravelAcceptance struct {
+	solutionMemory int
+	deviation      float64
+	record         float64
+}
+
+func NewRecordToRecordTravelAcceptance(solutionMemory int, deviation float64) *RecordToRecordTravelAcceptance {
+	checkSolutionMemory(solutionMemory)
+	if deviation < 0. {
+		panic("deviation must not be negative")
+	}
+	return &RecordToRecordTravelAcceptance{
+		solutionMemory: solutionMemory,
+		deviation:      deviation,
+		record:         math.MaxFloat64,
+	}
+}
+
+// Record returns the costs of the best solution seen so far.
+func (a *RecordToRecordTravelAcceptance) Record() float64 {
+	return a.record
+}
+
+// InformAlgorithmStarts resets the record to the best initial solution.
+func (a *RecordToRecordTravelAcceptance) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	a.record = math.MaxFloat64
+	if len(solutions) > 0 {
+		a.record = solution.BestOf(solutions).Cost()
+	}
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *RecordToRecordTravelAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *RecordToRecordTravelAcceptance) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *RecordToRecordTravelAcceptance) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	a.record = math.Min(a.record, newSolution.Cost())
+	if len(*solutions) < solutionMemory {
+		*solutions = append(*solutions, newSolution)
+		return true
+	}
+	if newSolution.Cost() > a.record*(1.+a.deviation) {
+		return false
+	}
+	(*solutions)[worstIndex(*solutions)] = newSolution
+	return true
+}
+
+func (a *RecordToRecordTravelAcceptance) String() string {
+	return fmt.Sprintf("[name=recordToRecordTravelAcceptance][solutionMemory=%d][deviation=%.4f]", a.solutionMemory, a.deviation)
+}
@@ -0,0 +1,95 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var (
+	_ SolutionAcceptor     = (*SchrimpfAcceptance)(nil)
+	_ SolutionPoolAcceptor = (*SchrimpfAcceptance)(nil)
+)
+
+// defaultInitialThresholdShare is the share of the costs of the best initial solution used as initial threshold
+// if none is set explicitly.
+const defaultInitialThresholdShare = .1
+
+// SchrimpfAcceptance is the threshold acceptance described by Schrimpf et al. (2000), Record breaking optimization
+// results using the ruin and recreate principle. A new solution replaces the worst solution in memory if its costs
+// are below the costs of the worst solution plus a threshold. The threshold cools down exponentially with the
+// iterations:
+//
+//	threshold(i) = initialThreshold * exp(-ln(2) * (i / maxIterations) / alpha)
+//
+// i.e. it halves whenever another alpha share of the iterations has passed.
+type SchrimpfAcceptance struct {
+	iterationProgress
+	solutionMemory      int
+	alpha               float64
+	initialThreshold    float64
+	initialThresholdSet bool
+	threshold           float64
+}
+
+func NewSchrimpfAcceptance(solutionMemory int, alpha float64) *SchrimpfAcceptance {
+	checkSolutionMemory(solutionMemory)
+	if alpha <= 0. {
+		panic("alpha must be greater than zero")
+	}
+	return &SchrimpfAcceptance{
+		solutionMemory: solutionMemory,
+		alpha:          alpha,
+	}
+}
+
+// SetInitialThreshold sets the threshold of the first iteration. If it is not set, the initial threshold is a
+// tenth of the costs of the best initial solution.
+func (a *SchrimpfAcceptance) SetInitialThreshold(initialThreshold float64) {
+	a.initialThreshold = initialThreshold
+	a.initialThresholdSet = true
+	a.threshold = initialThreshold
+}
+
+func (a *SchrimpfAcceptance) InitialThreshold() float64 {
+	return a.initialThreshold
+}
+
+// Threshold returns the threshold of the current iteration.
+func (a *SchrimpfAcceptance) Threshold() float64 {
+	return a.threshold
+}
+
+func (a *SchrimpfAcceptance) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformAlgorithmStarts(problem, algorithm, solutions)
+	if !a.initialThresholdSet && len(solutions) > 0 {
+		a.initialThreshold = defaultInitialThresholdShare * solution.BestOf(solutions).Cost()
+	}
+	a.threshold = a.initialThreshold
+}
+
+func (a *SchrimpfAcceptance) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformIterationStarts(i, problem, solutions)
+	a.threshold = a.initialThreshold * math.Exp(-math.Ln2*a.progress()/a.alpha)
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *SchrimpfAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *SchrimpfAcceptance) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *SchrimpfAcceptance) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	return acceptWithThreshold(solutions, newSolution, solutionMemory, a.threshold)
+}
+
+func (a *SchrimpfAcceptance) String() string {
+	return fmt.Sprintf("[name=schrimpfAcceptance][solutionMemory=%d][alpha=%.2f]", a.solutionMemory, a.alpha)
+}
@@ -0,0 +1,109 @@
+package acceptor
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"gsprit/util"
+	"math"
+	"math/rand/v2"
+)
+
+var (
+	_ SolutionAcceptor     = (*SimulatedAnnealingAcceptance)(nil)
+	_ SolutionPoolAcceptor = (*SimulatedAnnealingAcceptance)(nil)
+)
+
+// CoolingSchedule returns the temperature after the given share of the iterations, progress being between 0 and 1.
+type CoolingSchedule func(startTemperature, endTemperature, progress float64) float64
+
+// ExponentialCooling decreases the temperature geometrically from the start to the end temperature.
+func ExponentialCooling(startTemperature, endTemperature, progress float64) float64 {
+	return startTemperature * math.Pow(endTemperature/startTemperature, progress)
+}
+
+// LinearCooling decreases the temperature linearly from the start to the end temperature.
+func LinearCooling(startTemperature, endTemperature, progress float64) float64 {
+	return startTemperature + (endTemperature-startTemperature)*progress
+}
+
+// SimulatedAnnealingAcceptance accepts a new solution if it is better than the worst solution in memory and
+// otherwise with probability exp(-(newCosts - worstCosts) / temperature). The accepted solution replaces the
+// worst one. The temperature is updated at the start of every iteration according to the cooling schedule.
+type SimulatedAnnealingAcceptance struct {
+	iterationProgress
+	solutionMemory   int
+	startTemperature float64
+	endTemperature   float64
+	coolingSchedule  CoolingSchedule
+	temperature      float64
+	random           *rand.Rand
+}
+
+func NewSimulatedAnnealingAcceptance(solutionMemory int, startTemperature, endTemperature float64, coolingSchedule CoolingSchedule) *SimulatedAnnealingAcceptance {
+	checkSolutionMemory(solutionMemory)
+	if endTemperature <= 0. || startTemperature < endTemperature {
+		panic("temperatures must be greater than zero and the start temperature must not be lower than the end temperature")
+	}
+	if coolingSchedule == nil {
+		panic("cooling schedule must not be nil")
+	}
+	return &SimulatedAnnealingAcceptance{
+		solutionMemory:   solutionMemory,
+		startTemperature: startTemperature,
+		endTemperature:   endTemperature,
+		coolingSchedule:  coolingSchedule,
+		temperature:      startTemperature,
+		random:           util.NewRandom(util.DefaultSeed),
+	}
+}
+
+func (a *SimulatedAnnealingAcceptance) SetRandom(r *rand.Rand) {
+	a.random = r
+}
+
+// Temperature returns the temperature of the current iteration.
+func (a *SimulatedAnnealingAcceptance) Temperature() float64 {
+	return a.temperature
+}
+
+func (a *SimulatedAnnealingAcceptance) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformAlgorithmStarts(problem, algorithm, solutions)
+	a.temperature = a.startTemperature
+}
+
+func (a *SimulatedAnnealingAcceptance) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	a.iterationProgress.InformIterationStarts(i, problem, solutions)
+	a.temperature = a.coolingSchedule(a.startTemperature, a.endTemperature, a.progress())
+}
+
+// AcceptSolution takes the number of solutions as the solution memory, thus newSolution is accepted only if it
+// replaces one of solutions in place.
+func (a *SimulatedAnnealingAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return acceptInPlace(solutions, newSolution, a.accept)
+}
+
+func (a *SimulatedAnnealingAcceptance) AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	return a.accept(solutions, newSolution, a.solutionMemory)
+}
+
+func (a *SimulatedAnnealingAcceptance) accept(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int) bool {
+	if len(*solutions) < solutionMemory {
+		*solutions = append(*solutions, newSolution)
+		return true
+	}
+	worst := worstIndex(*solutions)
+	delta := newSolution.Cost() - (*solutions)[worst].Cost()
+	if delta < 0. || a.random.Float64() < math.Exp(-delta/a.temperature) {
+		(*solutions)[worst] = newSolution
+		return true
+	}
+	return false
+}
+
+func (a *SimulatedAnnealingAcceptance) String() string {
+	return fmt.Sprintf("[name=simulatedAnnealingAcceptance][solutionMemory=%d][startTemperature=%.2f][endTemperature=%.2f]",
+		a.solutionMemory, a.startTemperature, a.endTemperature)
+}
@@ -1,7 +1,104 @@
 package acceptor
 
-import "gsprit/problem/solution"
+import (
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
 
+// SolutionAcceptor decides whether a new solution enters the pool of solutions. Accepting a solution may
+// replace another one of solutions in place.
 type SolutionAcceptor interface {
 	AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool
 }
+
+// SolutionPoolAcceptor is implemented by acceptors that may also add a new solution to the pool, e.g. as long as
+// it holds fewer solutions than their solution memory. The search strategy passes the pool by pointer to those
+// and calls AcceptSolutionIntoPool instead of AcceptSolution.
+type SolutionPoolAcceptor interface {
+	AcceptSolutionIntoPool(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool
+}
+
+// acceptInPlace calls accept with the number of solutions as the solution memory, thus a new solution can only
+// replace one of solutions. An empty pool accepts nothing.
+func acceptInPlace(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	accept func(*[]*solution.VehicleRoutingProblemSolution, *solution.VehicleRoutingProblemSolution, int) bool) bool {
+	if len(solutions) == 0 {
+		return false
+	}
+	return accept(&solutions, newSolution, len(solutions))
+}
+
+// vehicleRoutingAlgorithm is identical to algorithm.VehicleRoutingAlgorithm, which cannot be imported here since
+// the algorithm package depends on acceptors.
+type vehicleRoutingAlgorithm = interface {
+	SearchSolutions() ([]*solution.VehicleRoutingProblemSolution, error)
+}
+
+// iterationProgress tracks the progress of the search for acceptors cooling down with the iterations. It
+// implements algorithm.AlgorithmStartsListener and algorithm.IterationStartsListener.
+type iterationProgress struct {
+	maxIterations    int
+	maxIterationsSet bool
+	iteration        int
+}
+
+// InformAlgorithmStarts takes the maximum number of iterations from the algorithm unless it has been set explicitly.
+func (p *iterationProgress) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm vehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	p.iteration = 0
+	if alg, ok := algorithm.(interface{ MaxIterations() int }); ok && !p.maxIterationsSet {
+		p.maxIterations = alg.MaxIterations()
+	}
+}
+
+func (p *iterationProgress) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	p.iteration = i
+}
+
+// SetMaxIterations sets the number of iterations after which the search is considered to be finished.
+func (p *iterationProgress) SetMaxIterations(maxIterations int) {
+	p.maxIterations = maxIterations
+	p.maxIterationsSet = true
+}
+
+// progress returns the share of iterations done, between 0 and 1.
+func (p *iterationProgress) progress() float64 {
+	if p.maxIterations <= 0 {
+		return 0.
+	}
+	return math.Min(1., float64(p.iteration)/float64(p.maxIterations))
+}
+
+// worstIndex returns the index of the most expensive solution.
+func worstIndex(solutions []*solution.VehicleRoutingProblemSolution) int {
+	worst := -1
+	for i, s := range solutions {
+		if worst < 0 || s.Cost() > solutions[worst].Cost() {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// acceptWithThreshold adds newSolution to the pool as long as it has less than solutionMemory solutions.
+// Afterwards newSolution replaces the worst solution if it is cheaper than the worst solution plus threshold.
+func acceptWithThreshold(solutions *[]*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution,
+	solutionMemory int, threshold float64) bool {
+	if len(*solutions) < solutionMemory {
+		*solutions = append(*solutions, newSolution)
+		return true
+	}
+	worst := worstIndex(*solutions)
+	if newSolution.Cost() < (*solutions)[worst].Cost()+threshold {
+		(*solutions)[worst] = newSolution
+		return true
+	}
+	return false
+}
+
+func checkSolutionMemory(solutionMemory int) {
+	if solutionMemory < 1 {
+		panic("solution memory must be at least 1")
+	}
+}
@@ -0,0 +1,158 @@
+package algorithm
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var (
+	_ StrategySelectedListener = (*AdaptiveStrategyWeights)(nil)
+	_ AlgorithmStartsListener  = (*AdaptiveStrategyWeights)(nil)
+)
+
+// WeightRecord holds the weights of the strategies after the given iteration.
+type WeightRecord struct {
+	Iteration int
+	Weights   map[string]float64
+}
+
+// AdaptiveStrategyWeights adapts the weights of the strategies of a SearchStrategyManager to their success as
+// in the adaptive large neighbourhood search of Ropke and Pisinger. Every run of a strategy scores
+//
+//   - globalBestScore if it has discovered a solution better than the best one known,
+//   - improvementScore if its solution has been accepted and is cheaper than the solution it has started from,
+//   - acceptedScore if its solution has merely been accepted.
+//
+// At the end of every segment of segmentLength iterations the weight of each strategy run in the segment becomes
+//
+//	(1 - reactionFactor) * weight + reactionFactor * score / runs
+//
+// and is passed to the manager with InformStrategyWeightChanged. Weights never drop below minWeight such that
+// every strategy keeps a chance to be selected.
+type AdaptiveStrategyWeights struct {
+	manager          *SearchStrategyManager
+	segmentLength    int
+	reactionFactor   float64
+	globalBestScore  float64
+	improvementScore float64
+	acceptedScore    float64
+	minWeight        float64
+	initialWeights   []float64
+	scores           map[string]float64
+	runs             map[string]int
+	bestCosts        float64
+	iteration        int
+	history          []WeightRecord
+}
+
+func NewAdaptiveStrategyWeights(manager *SearchStrategyManager, segmentLength int, reactionFactor float64) *AdaptiveStrategyWeights {
+	if segmentLength < 1 {
+		panic("segment length must be at least 1")
+	}
+	if reactionFactor < 0. || reactionFactor > 1. {
+		panic("reaction factor must be within [0,1]")
+	}
+	return &AdaptiveStrategyWeights{
+		manager:          manager,
+		segmentLength:    segmentLength,
+		reactionFactor:   reactionFactor,
+		globalBestScore:  33.,
+		improvementScore: 9.,
+		acceptedScore:    13.,
+		minWeight:        0.01,
+		initialWeights:   manager.Weights(),
+		scores:           make(map[string]float64),
+		runs:             make(map[string]int),
+		bestCosts:        math.MaxFloat64,
+		history:          make([]WeightRecord, 0),
+	}
+}
+
+// SetScores sets the scores of a new global best, an accepted improving and a merely accepted solution.
+func (w *AdaptiveStrategyWeights) SetScores(globalBest, improvement, accepted float64) {
+	w.globalBestScore = globalBest
+	w.improvementScore = improvement
+	w.acceptedScore = accepted
+}
+
+func (w *AdaptiveStrategyWeights) SetMinWeight(minWeight float64) {
+	w.minWeight = minWeight
+}
+
+// InformAlgorithmStarts restores the weights the strategies had when this was created and starts a new history.
+func (w *AdaptiveStrategyWeights) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm VehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	for i, strategy := range w.manager.Strategies() {
+		if i < len(w.initialWeights) {
+			_ = w.manager.InformStrategyWeightChanged(strategy.Id(), w.initialWeights[i])
+		}
+	}
+	w.bestCosts = math.MaxFloat64
+	if len(solutions) > 0 {
+		w.bestCosts = solution.BestOf(solutions).Cost()
+	}
+	w.iteration = 0
+	clear(w.scores)
+	clear(w.runs)
+	w.history = []WeightRecord{w.record()}
+}
+
+func (w *AdaptiveStrategyWeights) InformSelectedStrategy(discoveredSolution *DiscoveredSolution, vehicleRoutingProblem *vrp.VehicleRoutingProblem,
+	vehicleRoutingProblemSolutions []*solution.VehicleRoutingProblemSolution) {
+	w.iteration++
+	strategyId := discoveredSolution.StrategyId()
+	w.runs[strategyId]++
+	w.scores[strategyId] += w.score(discoveredSolution)
+	if w.iteration%w.segmentLength == 0 {
+		w.updateWeights()
+	}
+}
+
+func (w *AdaptiveStrategyWeights) score(discoveredSolution *DiscoveredSolution) float64 {
+	if costs := discoveredSolution.Solution().Cost(); costs < w.bestCosts {
+		w.bestCosts = costs
+		return w.globalBestScore
+	}
+	if !discoveredSolution.IsAccepted() {
+		return 0.
+	}
+	if discoveredSolution.IsImprovement() {
+		return w.improvementScore
+	}
+	return w.acceptedScore
+}
+
+// updateWeights closes the current segment.
+func (w *AdaptiveStrategyWeights) updateWeights() {
+	for strategyId, runs := range w.runs {
+		if runs == 0 {
+			continue
+		}
+		weight := (1.-w.reactionFactor)*w.manager.Weight(strategyId) + w.reactionFactor*w.scores[strategyId]/float64(runs)
+		_ = w.manager.InformStrategyWeightChanged(strategyId, math.Max(weight, w.minWeight))
+	}
+	clear(w.scores)
+	clear(w.runs)
+	w.history = append(w.history, w.record())
+}
+
+func (w *AdaptiveStrategyWeights) record() WeightRecord {
+	weights := make(map[string]float64)
+	for _, strategy := range w.manager.Strategies() {
+		weights[strategy.Id()] = w.manager.Weight(strategy.Id())
+	}
+	return WeightRecord{Iteration: w.iteration, Weights: weights}
+}
+
+// WeightHistory returns the weights at the start of the search followed by the weights after every segment.
+func (w *AdaptiveStrategyWeights) WeightHistory() []WeightRecord {
+	c := make([]WeightRecord, len(w.history))
+	copy(c, w.history)
+	return c
+}
+
+func (w *AdaptiveStrategyWeights) String() string {
+	return fmt.Sprintf("[name=adaptiveStrategyWeights][segmentLength=%d][reactionFactor=%.2f]", w.segmentLength, w.reactionFactor)
+}
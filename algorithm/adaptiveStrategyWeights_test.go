@@ -0,0 +1,97 @@
+package algorithm
+
+import (
+	"gsprit/algorithm/acceptor"
+	"gsprit/algorithm/selector"
+	"gsprit/problem/solution"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWeightedManager(t *testing.T) *SearchStrategyManager {
+	m := NewSearchStrategyManager()
+	assert.NoError(t, m.AddStrategy(NewSearchStrategy("a", nil, nil, nil), 1.))
+	assert.NoError(t, m.AddStrategy(NewSearchStrategy("b", nil, nil, nil), 1.))
+	return m
+}
+
+func discovered(cost float64, accepted, improvement bool, strategyId string) *DiscoveredSolution {
+	d := NewDiscoveredSolution(solution.NewVehicleRoutingProblemSolution(nil, cost), accepted, strategyId)
+	d.improvement = improvement
+	return d
+}
+
+func TestAdaptiveStrategyWeights_ShouldUpdateWeightsAtEndOfSegment(t *testing.T) {
+	m := newWeightedManager(t)
+	w := NewAdaptiveStrategyWeights(m, 2, 0.5)
+	w.SetScores(10., 5., 2.)
+	w.InformAlgorithmStarts(nil, nil, []*solution.VehicleRoutingProblemSolution{solution.NewVehicleRoutingProblemSolution(nil, 100.)})
+
+	w.InformSelectedStrategy(discovered(90., true, true, "a"), nil, nil)
+	assert.Equal(t, 1., m.Weight("a"))
+	w.InformSelectedStrategy(discovered(95., false, false, "b"), nil, nil)
+
+	assert.InDelta(t, 0.5*1.+0.5*10., m.Weight("a"), 1e-9)
+	assert.InDelta(t, 0.5, m.Weight("b"), 1e-9)
+}
+
+func TestAdaptiveStrategyWeights_ShouldScoreImprovementsAndAcceptance(t *testing.T) {
+	m := newWeightedManager(t)
+	w := NewAdaptiveStrategyWeights(m, 4, 1.)
+	w.SetScores(10., 5., 2.)
+	w.InformAlgorithmStarts(nil, nil, []*solution.VehicleRoutingProblemSolution{solution.NewVehicleRoutingProblemSolution(nil, 100.)})
+
+	w.InformSelectedStrategy(discovered(110., true, true, "a"), nil, nil)
+	w.InformSelectedStrategy(discovered(120., true, false, "a"), nil, nil)
+	w.InformSelectedStrategy(discovered(130., true, false, "b"), nil, nil)
+	w.InformSelectedStrategy(discovered(100., false, false, "b"), nil, nil)
+
+	assert.InDelta(t, (5.+2.)/2., m.Weight("a"), 1e-9)
+	assert.InDelta(t, 1., m.Weight("b"), 1e-9)
+}
+
+func TestAdaptiveStrategyWeights_ShouldKeepMinWeightAndUnusedWeights(t *testing.T) {
+	m := newWeightedManager(t)
+	w := NewAdaptiveStrategyWeights(m, 1, 1.)
+	w.SetMinWeight(0.2)
+	w.InformAlgorithmStarts(nil, nil, nil)
+	w.InformSelectedStrategy(discovered(100., true, false, "a"), nil, nil)
+
+	w.InformSelectedStrategy(discovered(100., false, false, "a"), nil, nil)
+
+	assert.Equal(t, 0.2, m.Weight("a"))
+	assert.Equal(t, 1., m.Weight("b"))
+}
+
+func TestAdaptiveStrategyWeights_ShouldRecordHistoryAndRestartWithInitialWeights(t *testing.T) {
+	m := newWeightedManager(t)
+	w := NewAdaptiveStrategyWeights(m, 2, 0.5)
+	w.InformAlgorithmStarts(nil, nil, nil)
+	for i := 0; i < 5; i++ {
+		w.InformSelectedStrategy(discovered(float64(100-i), true, true, "a"), nil, nil)
+	}
+
+	history := w.WeightHistory()
+
+	assert.Len(t, history, 3)
+	assert.Equal(t, 0, history[0].Iteration)
+	assert.Equal(t, map[string]float64{"a": 1., "b": 1.}, history[0].Weights)
+	assert.Equal(t, 2, history[1].Iteration)
+	assert.Equal(t, 4, history[2].Iteration)
+	assert.Greater(t, history[2].Weights["a"], history[1].Weights["a"])
+
+	w.InformAlgorithmStarts(nil, nil, nil)
+
+	assert.Equal(t, 1., m.Weight("a"))
+	assert.Len(t, w.WeightHistory(), 1)
+}
+
+func TestSearchStrategy_AcceptShouldReportImprovement(t *testing.T) {
+	s := NewSearchStrategy("s", selector.NewSelectBest(), acceptor.NewGreedyAcceptance(2), nil)
+	selected := solution.NewVehicleRoutingProblemSolution(nil, 10.)
+	solutions := []*solution.VehicleRoutingProblemSolution{selected}
+
+	assert.True(t, s.Accept(&solutions, selected, solution.NewVehicleRoutingProblemSolution(nil, 8.)).IsImprovement())
+	assert.False(t, s.Accept(&solutions, selected, solution.NewVehicleRoutingProblemSolution(nil, 12.)).IsImprovement())
+}
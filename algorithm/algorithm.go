@@ -3,6 +3,7 @@ package algorithm
 import (
 	"gsprit/problem"
 	"gsprit/problem/cost"
+	"gsprit/problem/solution"
 	"gsprit/problem/solution/route"
 	"gsprit/problem/vrp"
 )
@@ -23,3 +24,8 @@ type VehicleRoutingProblem interface {
 	Types() []problem.VehicleType
 	Vehicles() []problem.Vehicle
 }
+
+// InitialSolutionFactory creates the solution the search starts from if no initial solution has been provided.
+type InitialSolutionFactory interface {
+	CreateSolution(vrp *vrp.VehicleRoutingProblem) *solution.VehicleRoutingProblemSolution
+}
@@ -0,0 +1,394 @@
+package box
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/algorithm/acceptor"
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/construction"
+	"gsprit/algorithm/localsearch"
+	"gsprit/algorithm/module"
+	"gsprit/algorithm/objective"
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/ruin"
+	"gsprit/algorithm/selector"
+	"gsprit/algorithm/state"
+	"gsprit/algorithm/vra"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"gsprit/util"
+	"math/rand/v2"
+)
+
+// acceptors cooling down with the iterations are informed by the algorithm they are added to as listeners
+var (
+	_ algorithm.AlgorithmStartsListener = (*acceptor.SchrimpfAcceptance)(nil)
+	_ algorithm.IterationStartsListener = (*acceptor.SchrimpfAcceptance)(nil)
+	_ algorithm.AlgorithmStartsListener = (*acceptor.SimulatedAnnealingAcceptance)(nil)
+	_ algorithm.IterationStartsListener = (*acceptor.SimulatedAnnealingAcceptance)(nil)
+	_ algorithm.AlgorithmStartsListener = (*acceptor.RecordToRecordTravelAcceptance)(nil)
+	_ algorithm.AlgorithmStartsListener = (*acceptor.LateAcceptanceHillClimbing)(nil)
+	_ algorithm.AlgorithmStartsListener = (*acceptor.GreatDelugeAcceptance)(nil)
+	_ algorithm.IterationStartsListener = (*acceptor.GreatDelugeAcceptance)(nil)
+)
+
+// Strategy identifies one of the built-in ruin-and-recreate search strategies.
+type Strategy string
+
+const (
+	RadialBest   Strategy = "radial_best"
+	RadialRegret Strategy = "radial_regret"
+	RandomBest   Strategy = "random_best"
+	RandomRegret Strategy = "random_regret"
+	WorstBest    Strategy = "worst_best"
+	WorstRegret  Strategy = "worst_regret"
+	StringBest   Strategy = "string_best"
+	StringRegret Strategy = "string_regret"
+)
+
+// Strategies lists the built-in strategies in the order they are added to the algorithm.
+var Strategies = []Strategy{RadialBest, RadialRegret, RandomBest, RandomRegret, WorstBest, WorstRegret, StringBest, StringRegret}
+
+var defaultWeights = map[Strategy]float64{
+	RadialBest:   0.,
+	RadialRegret: .5,
+	RandomBest:   .5,
+	RandomRegret: .5,
+	WorstBest:    0.,
+	WorstRegret:  1.,
+	StringBest:   0.,
+	StringRegret: 1.,
+}
+
+// Construction identifies one of the built-in heuristics constructing the initial solution.
+type Construction string
+
+const (
+	BestInsertionConstruction   Construction = "best_insertion"
+	RegretInsertionConstruction Construction = "regret_insertion"
+	SavingsConstruction         Construction = "savings"
+)
+
+const (
+	radialShare = .3
+	randomShare = .5
+	worstShare  = .3
+)
+
+type weightedStrategy struct {
+	strategy *algorithm.SearchStrategy
+	weight   float64
+}
+
+// Builder assembles a ready-to-run VehicleRoutingAlgorithm: ruin-and-recreate strategies combining radial, random,
+// worst and string removal with best and regret insertion, the default objective function, a construction
+// heuristic for the initial solution, greedy acceptance and the selection of the best solution.
+//
+// Each piece can be replaced. Built-in strategies with a weight of zero are not added to the algorithm.
+type Builder struct {
+	vrp                    *vrp.VehicleRoutingProblem
+	stateManager           *state.StateManager
+	constraintManager      *constraint.ConstraintManager
+	objectiveFunction      solution.SolutionCostCalculator
+	solutionAcceptor       acceptor.SolutionAcceptor
+	solutionSelector       selector.SolutionSelector
+	initialSolutionFactory algorithm.InitialSolutionFactory
+	construction           Construction
+	weights                map[Strategy]float64
+	customStrategies       []weightedStrategy
+	maxIterations          int
+	numberOfThreads        int
+	seed                   uint64
+	localSearch            bool
+}
+
+func NewBuilder(vrp *vrp.VehicleRoutingProblem) *Builder {
+	weights := make(map[Strategy]float64, len(defaultWeights))
+	for s, w := range defaultWeights {
+		weights[s] = w
+	}
+	return &Builder{
+		vrp:             vrp,
+		construction:    BestInsertionConstruction,
+		weights:         weights,
+		maxIterations:   2000,
+		numberOfThreads: 1,
+		seed:            util.DefaultSeed,
+	}
+}
+
+// CreateAlgorithm creates an algorithm with the default configuration.
+func CreateAlgorithm(vrp *vrp.VehicleRoutingProblem) *vra.VehicleRoutingAlgorithm {
+	alg, err := NewBuilder(vrp).BuildAlgorithm()
+	if err != nil {
+		// the default configuration is always valid
+		panic(err)
+	}
+	return alg
+}
+
+// SetStateAndConstraintManager replaces the managers shared by all insertion strategies, e.g. to add custom
+// states and constraints. The constraint manager has to be based on the state manager.
+func (b *Builder) SetStateAndConstraintManager(stateManager *state.StateManager, constraintManager *constraint.ConstraintManager) *Builder {
+	b.stateManager = stateManager
+	b.constraintManager = constraintManager
+	return b
+}
+
+func (b *Builder) SetObjectiveFunction(objectiveFunction solution.SolutionCostCalculator) *Builder {
+	b.objectiveFunction = objectiveFunction
+	return b
+}
+
+func (b *Builder) SetSolutionAcceptor(solutionAcceptor acceptor.SolutionAcceptor) *Builder {
+	b.solutionAcceptor = solutionAcceptor
+	return b
+}
+
+func (b *Builder) SetSolutionSelector(solutionSelector selector.SolutionSelector) *Builder {
+	b.solutionSelector = solutionSelector
+	return b
+}
+
+func (b *Builder) SetInitialSolutionFactory(initialSolutionFactory algorithm.InitialSolutionFactory) *Builder {
+	b.initialSolutionFactory = initialSolutionFactory
+	return b
+}
+
+// SetConstruction selects the built-in heuristic constructing the initial solution. It is ignored if an initial
+// solution factory is set.
+func (b *Builder) SetConstruction(construction Construction) *Builder {
+	b.construction = construction
+	return b
+}
+
+// SetStrategyWeight sets the weight of a built-in strategy. A weight of zero disables it.
+func (b *Builder) SetStrategyWeight(strategy Strategy, weight float64) *Builder {
+	b.weights[strategy] = weight
+	return b
+}
+
+// AddSearchStrategy adds a custom strategy in addition to the built-in ones.
+func (b *Builder) AddSearchStrategy(strategy *algorithm.SearchStrategy, weight float64) *Builder {
+	b.customStrategies = append(b.customStrategies, weightedStrategy{strategy: strategy, weight: weight})
+	return b
+}
+
+func (b *Builder) SetMaxIterations(maxIterations int) *Builder {
+	b.maxIterations = maxIterations
+	return b
+}
+
+func (b *Builder) SetNumberOfThreads(numberOfThreads int) *Builder {
+	b.numberOfThreads = numberOfThreads
+	return b
+}
+
+// SetLocalSearch enables a local search improving the routes after every ruin and recreate step, first by
+// exchanging activities between routes and then by reordering the activities of every route.
+func (b *Builder) SetLocalSearch(localSearch bool) *Builder {
+	b.localSearch = localSearch
+	return b
+}
+
+// SetSeed sets the seed all random number generators of the algorithm are derived from: strategy selection,
+// ruin and insertion strategies, the construction, acceptor and selector and the workers of a parallel search
+// each get an independent generator of their own. Generators injected into the acceptor or selector beforehand
+// are replaced. Algorithms built with the same seed for the same problem find the same solutions.
+func (b *Builder) SetSeed(seed uint64) *Builder {
+	b.seed = seed
+	return b
+}
+
+func (b *Builder) BuildAlgorithm() (*vra.VehicleRoutingAlgorithm, error) {
+	if b.maxIterations < 0 {
+		return nil, fmt.Errorf("max iterations must not be negative")
+	}
+	if b.numberOfThreads < 1 {
+		return nil, fmt.Errorf("number of threads must be at least 1")
+	}
+	if b.numberOfThreads > 1 && (b.stateManager != nil || len(b.customStrategies) > 0) {
+		return nil, fmt.Errorf("custom state and constraint managers and custom strategies cannot be shared by parallel workers")
+	}
+	switch b.construction {
+	case BestInsertionConstruction, RegretInsertionConstruction, SavingsConstruction:
+	default:
+		return nil, fmt.Errorf("unknown construction %s", b.construction)
+	}
+	for s, w := range b.weights {
+		if _, ok := defaultWeights[s]; !ok {
+			return nil, fmt.Errorf("unknown strategy %s", s)
+		}
+		if w < 0. {
+			return nil, fmt.Errorf("weight of strategy %s is lower than zero", s)
+		}
+	}
+	stateManager, constraintManager := b.stateManager, b.constraintManager
+	if stateManager == nil {
+		stateManager = state.NewStateManager(b.vrp)
+	}
+	if constraintManager == nil {
+		constraintManager = constraint.NewConstraintManager(b.vrp, stateManager)
+	}
+	objectiveFunction := b.objectiveFunction
+	if objectiveFunction == nil {
+		objectiveFunction = objective.NewDefaultObjectiveFunction(b.vrp)
+	}
+	solutionAcceptor := b.solutionAcceptor
+	if solutionAcceptor == nil {
+		solutionAcceptor = acceptor.NewGreedyAcceptance(1)
+	}
+	solutionSelector := b.solutionSelector
+	if solutionSelector == nil {
+		solutionSelector = selector.NewSelectBest()
+	}
+
+	randomSource := util.NewRandomSource(b.seed)
+	for _, c := range []any{solutionAcceptor, solutionSelector} {
+		if r, ok := c.(randomized); ok {
+			r.SetRandom(randomSource.Next())
+		}
+	}
+
+	initialSolutionFactory := b.initialSolutionFactory
+	if initialSolutionFactory == nil {
+		var insertion interface {
+			recreate.InsertionStrategy
+			randomized
+		}
+		if b.construction == RegretInsertionConstruction {
+			insertion = recreate.NewRegretInsertion(b.vrp, stateManager, constraintManager)
+		} else {
+			insertion = recreate.NewBestInsertion(b.vrp, stateManager, constraintManager)
+		}
+		insertion.SetRandom(randomSource.Next())
+		if b.construction == SavingsConstruction {
+			initialSolutionFactory = construction.NewSavingsConstruction(insertion, constraintManager, objectiveFunction)
+		} else {
+			initialSolutionFactory = construction.NewInsertionInitialSolutionFactory(insertion, objectiveFunction)
+		}
+	}
+
+	factory := &strategyFactory{
+		vrp:               b.vrp,
+		weights:           b.weights,
+		objectiveFunction: objectiveFunction,
+		solutionAcceptor:  solutionAcceptor,
+		solutionSelector:  solutionSelector,
+		localSearch:       b.localSearch,
+	}
+	for _, s := range Strategies {
+		if usesNeighborhoods(s) && b.weights[s] > 0. {
+			factory.jobNeighborhoods = ruin.NewJobNeighborhoods(b.vrp, ruin.NewDefaultJobDistance(b.vrp.TransportCosts()), len(b.vrp.Jobs()))
+			break
+		}
+	}
+	strategyManager, err := factory.createStrategies(stateManager, constraintManager, randomSource)
+	if err != nil {
+		return nil, err
+	}
+	strategyManager.SetRandom(randomSource.Next())
+	for _, ws := range b.customStrategies {
+		if err := strategyManager.AddStrategy(ws.strategy, ws.weight); err != nil {
+			return nil, err
+		}
+	}
+	if len(strategyManager.Strategies()) == 0 {
+		return nil, fmt.Errorf("no search strategy with a positive weight")
+	}
+
+	alg := vra.NewVehicleRoutingAlgorithm(b.vrp, strategyManager)
+	alg.SetObjectiveFunction(objectiveFunction)
+	alg.SetInitialSolutionFactory(initialSolutionFactory)
+	alg.SetMaxIterations(b.maxIterations)
+	alg.SetNumberOfThreads(b.numberOfThreads)
+	alg.SetSeed(b.seed)
+	if b.numberOfThreads > 1 {
+		// every worker gets strategies of its own, based on managers of its own
+		alg.SetSearchStrategyManagerFactory(func(worker int, random *rand.Rand) (*algorithm.SearchStrategyManager, error) {
+			workerStateManager := state.NewStateManager(b.vrp)
+			return factory.createStrategies(workerStateManager, constraint.NewConstraintManager(b.vrp, workerStateManager),
+				util.NewRandomSource(random.Uint64()))
+		})
+	}
+	// acceptors and selectors may depend on the progress of the search
+	alg.AddListener(solutionAcceptor)
+	alg.AddListener(solutionSelector)
+	return alg, nil
+}
+
+func usesNeighborhoods(s Strategy) bool {
+	return s == RadialBest || s == RadialRegret || s == StringBest || s == StringRegret
+}
+
+// strategyFactory creates the built-in strategies. Acceptor, selector, objective function and job neighborhoods
+// are shared by all strategies created, ruin and insertion strategies are created anew.
+type strategyFactory struct {
+	vrp               *vrp.VehicleRoutingProblem
+	weights           map[Strategy]float64
+	objectiveFunction solution.SolutionCostCalculator
+	solutionAcceptor  acceptor.SolutionAcceptor
+	solutionSelector  selector.SolutionSelector
+	jobNeighborhoods  *ruin.JobNeighborhoods
+	localSearch       bool
+}
+
+// randomized is implemented by components drawing random numbers.
+type randomized interface {
+	SetRandom(r *rand.Rand)
+}
+
+// createStrategies creates the built-in strategies with a positive weight. Their insertion strategies are based on
+// the given managers, every ruin and insertion strategy gets a generator of its own from randomSource.
+func (f *strategyFactory) createStrategies(stateManager *state.StateManager, constraintManager *constraint.ConstraintManager,
+	randomSource *util.RandomSource) (*algorithm.SearchStrategyManager, error) {
+	bestInsertion := recreate.NewBestInsertion(f.vrp, stateManager, constraintManager)
+	bestInsertion.SetRandom(randomSource.Next())
+	regretInsertion := recreate.NewRegretInsertion(f.vrp, stateManager, constraintManager)
+	regretInsertion.SetRandom(randomSource.Next())
+	strategyManager := algorithm.NewSearchStrategyManager()
+	for _, s := range Strategies {
+		weight := f.weights[s]
+		if weight == 0. {
+			continue
+		}
+		var ruinStrategy interface {
+			ruin.RuinStrategy
+			randomized
+		}
+		switch s {
+		case RadialBest, RadialRegret:
+			ruinStrategy = ruin.NewRadialRuinWithNeighborhoods(f.vrp, radialShare, f.jobNeighborhoods)
+		case StringBest, StringRegret:
+			ruinStrategy = ruin.NewStringRuinWithNeighborhoods(f.vrp, f.jobNeighborhoods)
+		case RandomBest, RandomRegret:
+			ruinStrategy = ruin.NewRandomRuin(f.vrp, randomShare)
+		case WorstBest, WorstRegret:
+			ruinStrategy = ruin.NewWorstRuin(f.vrp, worstShare)
+		}
+		ruinStrategy.SetRandom(randomSource.Next())
+		// the states have to be updated whenever jobs are removed
+		ruinStrategy.AddListener(stateManager)
+		var insertion recreate.InsertionStrategy = bestInsertion
+		if s == RadialRegret || s == RandomRegret || s == WorstRegret || s == StringRegret {
+			insertion = regretInsertion
+		}
+		strategy := algorithm.NewSearchStrategy(string(s), f.solutionSelector, f.solutionAcceptor, f.objectiveFunction)
+		strategy.SetName(string(s))
+		if err := strategy.AddModule(module.NewRuinAndRecreateModule(string(s), insertion, ruinStrategy)); err != nil {
+			return nil, err
+		}
+		if f.localSearch {
+			if err := strategy.AddModule(localsearch.NewInterRouteLocalSearch(f.vrp, stateManager, constraintManager)); err != nil {
+				return nil, err
+			}
+			if err := strategy.AddModule(localsearch.NewIntraRouteLocalSearch(f.vrp, stateManager, constraintManager)); err != nil {
+				return nil, err
+			}
+		}
+		if err := strategyManager.AddStrategy(strategy, weight); err != nil {
+			return nil, err
+		}
+	}
+	return strategyManager, nil
+}
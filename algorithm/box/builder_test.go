@@ -0,0 +1,420 @@
+package box
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"gsprit/algorithm"
+	"gsprit/algorithm/acceptor"
+	"gsprit/algorithm/selector"
+	"gsprit/algorithm/termination"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newProblem(nOfJobs int) *vrp.VehicleRoutingProblem {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 4).SetFixedCost(10.).Build()
+	v := vehicle.NewVehicleBuilder("v").SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v)
+	for i := 0; i < nOfJobs; i++ {
+		x, y := float64(i%4)*10., float64(i/4)*10.
+		b.AddJob(job.NewServiceBuilder[*job.Service](fmt.Sprintf("s%d", i)).
+			SetLocation(problem.NewLocationWithCoordinate(x, y)).AddSizeDimension(0, 1).Build())
+	}
+	return b.Build()
+}
+
+func TestCreateAlgorithm_ShouldFindSolutionServingAllJobs(t *testing.T) {
+	p := newProblem(12)
+	alg := CreateAlgorithm(p)
+	alg.SetMaxIterations(50)
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	best := solution.BestOf(solutions)
+	assert.Empty(t, best.UnassignedJobs())
+	nOfJobs := 0
+	for _, r := range best.Routes() {
+		assert.LessOrEqual(t, r.TourActivities().JobSize(), 4)
+		nOfJobs += r.TourActivities().JobSize()
+	}
+	assert.Equal(t, 12, nOfJobs)
+	assert.Equal(t, alg.ObjectiveFunction().Costs(best), best.Cost())
+}
+
+func TestBuilder_ShouldConstructInitialSolutionWithSavings(t *testing.T) {
+	p := newProblem(12)
+	alg, err := NewBuilder(p).SetConstruction(SavingsConstruction).SetMaxIterations(0).BuildAlgorithm()
+	assert.NoError(t, err)
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	best := solution.BestOf(solutions)
+	assert.Empty(t, best.UnassignedJobs())
+	assert.Len(t, best.Routes(), 3)
+}
+
+func TestBuilder_ShouldApplyOverrides(t *testing.T) {
+	p := newProblem(4)
+	a := acceptor.NewGreedyAcceptance(3)
+	alg, err := NewBuilder(p).
+		SetSolutionAcceptor(a).
+		SetStrategyWeight(WorstRegret, 0.).
+		SetStrategyWeight(RadialBest, 2.).
+		SetMaxIterations(10).
+		SetNumberOfThreads(2).
+		BuildAlgorithm()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, alg.MaxIterations())
+	assert.Equal(t, 2, alg.NumberOfThreads())
+	assert.Equal(t, 2., alg.SearchStrategyManager().Weight(string(RadialBest)))
+	for _, s := range alg.SearchStrategyManager().Strategies() {
+		assert.NotEqual(t, string(WorstRegret), s.Id())
+	}
+	assert.Contains(t, alg.AlgorithmListeners().AlgorithmListeners(), algorithm.VehicleRoutingAlgorithmListener(a))
+}
+
+func TestBuilder_AcceptorShouldBeInformedAboutIterations(t *testing.T) {
+	p := newProblem(8)
+	a := acceptor.NewSchrimpfAcceptance(1, .5)
+	a.SetInitialThreshold(100.)
+	alg, err := NewBuilder(p).SetSolutionAcceptor(a).SetMaxIterations(20).BuildAlgorithm()
+	assert.NoError(t, err)
+
+	_, err = alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 25., a.Threshold(), 1e-9)
+}
+
+func TestBuilder_ShouldSearchWithPoolOfLateAcceptedSolutions(t *testing.T) {
+	p := newProblem(8)
+	alg, err := NewBuilder(p).SetSolutionAcceptor(acceptor.NewLateAcceptanceHillClimbing(3, 5)).
+		SetSolutionSelector(selector.NewTournamentSelection(2)).SetMaxIterations(30).BuildAlgorithm()
+	assert.NoError(t, err)
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	// the pool plus the best solution ever found
+	assert.LessOrEqual(t, len(solutions), 4)
+	assert.Empty(t, solution.BestOf(solutions).UnassignedJobs())
+}
+
+func TestBuilder_TerminationCriteriaShouldStopSearch(t *testing.T) {
+	p := newProblem(8)
+	alg := CreateAlgorithm(p)
+	alg.SetMaxIterations(1000)
+	c := &iterationCounter{}
+	alg.AddListener(c)
+	alg.AddTerminationCriterion(termination.NewIterationWithoutImprovementTermination(5))
+
+	_, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	assert.Less(t, c.iterations, 1000)
+}
+
+type iterationCounter struct {
+	iterations int
+}
+
+func (c *iterationCounter) InformIterationEnds(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	c.iterations = i
+}
+
+type cancelAtIteration struct {
+	iteration int
+	cancel    context.CancelFunc
+}
+
+func (c *cancelAtIteration) InformIterationEnds(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	if i == c.iteration {
+		c.cancel()
+	}
+}
+
+func TestSearchSolutionsWithContext_ShouldStopAfterCurrentIterationWhenCancelled(t *testing.T) {
+	p := newProblem(8)
+	alg := CreateAlgorithm(p)
+	alg.SetMaxIterations(1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &iterationCounter{}
+	alg.AddListener(c)
+	alg.AddListener(&cancelAtIteration{iteration: 3, cancel: cancel})
+
+	solutions, err := alg.SearchSolutionsWithContext(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, c.iterations)
+	assert.Contains(t, solutions, alg.BestEver())
+	assert.Same(t, alg.BestEver(), solution.BestOf(solutions))
+}
+
+func TestSearchSolutionsWithContext_ShouldStopAtDeadline(t *testing.T) {
+	p := newProblem(8)
+	alg := CreateAlgorithm(p)
+	alg.SetMaxIterations(1000)
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	solutions, err := alg.SearchSolutionsWithContext(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// the initial solution
+	assert.NotNil(t, solution.BestOf(solutions))
+}
+
+type iterationRecorder struct {
+	started, ended []int
+}
+
+func (r *iterationRecorder) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	r.started = append(r.started, i)
+}
+
+func (r *iterationRecorder) InformIterationEnds(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	r.ended = append(r.ended, i)
+}
+
+func TestBuilder_ParallelSearchShouldInformListenersInOrder(t *testing.T) {
+	p := newProblem(12)
+	alg, err := NewBuilder(p).SetSolutionAcceptor(acceptor.NewGreedyAcceptance(3)).
+		SetMaxIterations(100).SetNumberOfThreads(4).BuildAlgorithm()
+	assert.NoError(t, err)
+	r := &iterationRecorder{}
+	alg.AddListener(r)
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	assert.Len(t, r.started, 100)
+	for i := range r.started {
+		assert.Equal(t, i+1, r.started[i])
+		assert.Equal(t, i+1, r.ended[i])
+	}
+	best := solution.BestOf(solutions)
+	assert.Empty(t, best.UnassignedJobs())
+	assert.Equal(t, alg.ObjectiveFunction().Costs(best), best.Cost())
+}
+
+// moduleEventLog records the ruin and insertion events per iteration, one letter per event.
+type moduleEventLog struct {
+	iteration int
+	running   bool
+	events    map[int]string
+}
+
+func (l *moduleEventLog) record(event string) {
+	if !l.running {
+		event = "!"
+	}
+	l.events[l.iteration] += event
+}
+
+func (l *moduleEventLog) InformIterationStarts(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	l.iteration, l.running = i, true
+}
+
+func (l *moduleEventLog) InformIterationEnds(i int, problem *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) {
+	l.running = false
+}
+
+func (l *moduleEventLog) RuinStarts(routes []*route.VehicleRoute) {
+	l.record("S")
+}
+
+func (l *moduleEventLog) RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	l.record("E")
+}
+
+func (l *moduleEventLog) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	l.record("r")
+}
+
+func (l *moduleEventLog) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	l.record("s")
+}
+
+func (l *moduleEventLog) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	l.record("i")
+}
+
+func (l *moduleEventLog) InformInsertionEnds(vehicleRoutes []*route.VehicleRoute, badJobs []problem.Job) {
+	l.record("e")
+}
+
+func TestBuilder_ParallelSearchShouldInformModuleListenersInOrder(t *testing.T) {
+	p := newProblem(12)
+	alg, err := NewBuilder(p).SetMaxIterations(50).SetNumberOfThreads(4).BuildAlgorithm()
+	assert.NoError(t, err)
+	l := &moduleEventLog{events: make(map[int]string)}
+	alg.AddListener(l)
+
+	_, err = alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	assert.Len(t, l.events, 50)
+	iteration := regexp.MustCompile(`^Sr*Esi+e$`)
+	for i := 1; i <= 50; i++ {
+		assert.Regexp(t, iteration, l.events[i], "iteration %d", i)
+	}
+}
+
+func TestBuilder_ParallelSearchShouldStopWhenCancelled(t *testing.T) {
+	p := newProblem(8)
+	alg, err := NewBuilder(p).SetMaxIterations(1000).SetNumberOfThreads(3).BuildAlgorithm()
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &iterationCounter{}
+	alg.AddListener(c)
+	alg.AddListener(&cancelAtIteration{iteration: 5, cancel: cancel})
+
+	solutions, err := alg.SearchSolutionsWithContext(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 5, c.iterations)
+	assert.Same(t, alg.BestEver(), solution.BestOf(solutions))
+}
+
+func TestBuilder_ParallelSearchRequiresStrategiesPerWorker(t *testing.T) {
+	p := newProblem(4)
+	alg, err := NewBuilder(p).SetMaxIterations(10).SetNumberOfThreads(2).BuildAlgorithm()
+	assert.NoError(t, err)
+	alg.SetSearchStrategyManagerFactory(nil)
+
+	_, err = alg.SearchSolutions()
+
+	assert.Error(t, err)
+	_, err = NewBuilder(p).SetNumberOfThreads(2).AddSearchStrategy(alg.SearchStrategyManager().Strategies()[0], 1.).BuildAlgorithm()
+	assert.Error(t, err)
+}
+
+func TestBuilder_InvalidConfigurationsMustFail(t *testing.T) {
+	p := newProblem(4)
+	_, err := NewBuilder(p).SetStrategyWeight("unknown", 1.).BuildAlgorithm()
+	assert.Error(t, err)
+	_, err = NewBuilder(p).SetStrategyWeight(RandomBest, -1.).BuildAlgorithm()
+	assert.Error(t, err)
+	_, err = NewBuilder(p).SetNumberOfThreads(0).BuildAlgorithm()
+	assert.Error(t, err)
+	_, err = NewBuilder(p).SetConstruction("unknown").BuildAlgorithm()
+	assert.Error(t, err)
+
+	b := NewBuilder(p)
+	for _, s := range Strategies {
+		b.SetStrategyWeight(s, 0.)
+	}
+	_, err = b.BuildAlgorithm()
+	assert.Error(t, err)
+}
+
+func TestBuilder_AdaptiveStrategyWeightsShouldRecordSegments(t *testing.T) {
+	p := newProblem(8)
+	alg, err := NewBuilder(p).SetMaxIterations(50).BuildAlgorithm()
+	assert.NoError(t, err)
+	weights := algorithm.NewAdaptiveStrategyWeights(alg.SearchStrategyManager(), 10, 0.2)
+	alg.AddListener(weights)
+
+	_, err = alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	assert.Len(t, weights.WeightHistory(), 6)
+	assert.NotEqual(t, weights.WeightHistory()[0].Weights, weights.WeightHistory()[5].Weights)
+}
+
+type discoveryRecorder struct {
+	discoveries []string
+}
+
+func (r *discoveryRecorder) InformSelectedStrategy(discoveredSolution *algorithm.DiscoveredSolution, problem *vrp.VehicleRoutingProblem,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	r.discoveries = append(r.discoveries, fmt.Sprintf("%s:%.6f:%v", discoveredSolution.StrategyId(), discoveredSolution.Solution().Cost(),
+		discoveredSolution.IsAccepted()))
+}
+
+func searchWithSeed(t *testing.T, seed uint64, numberOfThreads int) []string {
+	alg, err := NewBuilder(newProblem(16)).
+		SetSolutionAcceptor(acceptor.NewSimulatedAnnealingAcceptance(2, 10., 0.1, acceptor.ExponentialCooling)).
+		SetSolutionSelector(selector.NewSelectRandom()).
+		SetSeed(seed).SetMaxIterations(60).SetNumberOfThreads(numberOfThreads).BuildAlgorithm()
+	assert.NoError(t, err)
+	r := &discoveryRecorder{}
+	alg.AddListener(r)
+	_, err = alg.SearchSolutions()
+	assert.NoError(t, err)
+	return r.discoveries
+}
+
+func TestBuilder_SameSeedShouldDiscoverSameSolutions(t *testing.T) {
+	for _, numberOfThreads := range []int{1, 3} {
+		first := searchWithSeed(t, 42, numberOfThreads)
+		second := searchWithSeed(t, 42, numberOfThreads)
+
+		assert.Len(t, first, 60)
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, searchWithSeed(t, 43, numberOfThreads))
+	}
+}
+
+func TestBuilder_LocalSearchShouldImproveEveryStep(t *testing.T) {
+	p := newProblem(12)
+	alg, err := NewBuilder(p).SetLocalSearch(true).SetMaxIterations(30).BuildAlgorithm()
+	assert.NoError(t, err)
+	for _, strategy := range alg.SearchStrategyManager().Strategies() {
+		modules := strategy.SearchStrategyModules()
+		assert.Equal(t, "interRouteLocalSearch", modules[len(modules)-2].Name())
+		assert.Equal(t, "intraRouteLocalSearch", modules[len(modules)-1].Name())
+	}
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	best := solution.BestOf(solutions)
+	assert.Empty(t, best.UnassignedJobs())
+	assert.Equal(t, alg.ObjectiveFunction().Costs(best), best.Cost())
+}
+
+func TestBuilder_HeterogeneousFiniteFleetShouldUseEveryVehicleOnce(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).SetFleetSize(vrp.Finite)
+	for i, capacity := range []int{1, 1, 3, 3, 6} {
+		vt := vehicle.NewVehicleTypeBuilder(fmt.Sprintf("type%d", capacity)).AddCapacityDimension(0, capacity).
+			SetFixedCost(10. * float64(capacity)).Build()
+		b.AddVehicle(vehicle.NewVehicleBuilder(fmt.Sprintf("v%d", i)).SetType(vt).
+			SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build())
+	}
+	for i := 0; i < 12; i++ {
+		b.AddJob(job.NewServiceBuilder[*job.Service](fmt.Sprintf("s%d", i)).
+			SetLocation(problem.NewLocationWithCoordinate(float64(i%4)*10., float64(i/4)*10.)).AddSizeDimension(0, 1).Build())
+	}
+	p := b.Build()
+	alg := CreateAlgorithm(p)
+	alg.SetMaxIterations(50)
+
+	solutions, err := alg.SearchSolutions()
+
+	assert.NoError(t, err)
+	best := solution.BestOf(solutions)
+	assert.Empty(t, best.UnassignedJobs())
+	used := make(map[string]bool)
+	for _, r := range best.Routes() {
+		assert.False(t, used[r.Vehicle().Id()])
+		used[r.Vehicle().Id()] = true
+		assert.LessOrEqual(t, r.TourActivities().JobSize(), r.Vehicle().Type().CapacityDimensions().Get(0))
+	}
+	assert.Equal(t, alg.ObjectiveFunction().Costs(best), best.Cost())
+}
@@ -0,0 +1,48 @@
+package constraint
+
+import "gsprit/problem"
+
+// ConstraintsStatus is the result of evaluating a hard activity constraint.
+type ConstraintsStatus int
+
+const (
+	// Fulfilled means the activity can be inserted at the evaluated position.
+	Fulfilled ConstraintsStatus = iota
+	// NotFulfilled means the activity cannot be inserted at the evaluated position, but maybe at a later one.
+	NotFulfilled
+	// NotFulfilledBreak means the activity can neither be inserted at the evaluated position nor at any later one.
+	NotFulfilledBreak
+)
+
+func (s ConstraintsStatus) String() string {
+	switch s {
+	case Fulfilled:
+		return "FULFILLED"
+	case NotFulfilled:
+		return "NOT_FULFILLED"
+	case NotFulfilledBreak:
+		return "NOT_FULFILLED_BREAK"
+	}
+	return "UNKNOWN"
+}
+
+// HardRouteConstraint decides whether a job can be inserted into a route at all.
+type HardRouteConstraint interface {
+	Fulfilled(iContext *JobInsertionContext) bool
+}
+
+// HardActivityConstraint decides whether newAct can be inserted between prevAct and nextAct, where
+// prevActDepTime is the time the vehicle leaves prevAct.
+type HardActivityConstraint interface {
+	Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity, prevActDepTime float64) ConstraintsStatus
+}
+
+// SoftRouteConstraint adds costs to the insertion of a job into a route.
+type SoftRouteConstraint interface {
+	Costs(iContext *JobInsertionContext) float64
+}
+
+// SoftActivityConstraint adds costs to the insertion of newAct between prevAct and nextAct.
+type SoftActivityConstraint interface {
+	Costs(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity, prevActDepTime float64) float64
+}
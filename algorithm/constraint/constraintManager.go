@@ -0,0 +1,124 @@
+package constraint
+
+import (
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/vrp"
+)
+
+// ConstraintManager bundles the hard and soft constraints consulted when a job is inserted.
+//
+// It comes with the default constraints of a vehicle routing problem: the capacity of the vehicle, time windows
+// including the latest arrival of the vehicle, the skills required by a job and the precedence of the pickup of
+// a shipment over its delivery. Further constraints are evaluated in the order they are added.
+type ConstraintManager struct {
+	vrp                     *vrp.VehicleRoutingProblem
+	stateManager            *state.StateManager
+	hardRouteConstraints    []HardRouteConstraint
+	hardActivityConstraints []HardActivityConstraint
+	softRouteConstraints    []SoftRouteConstraint
+	softActivityConstraints []SoftActivityConstraint
+}
+
+func NewConstraintManager(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager) *ConstraintManager {
+	cm := &ConstraintManager{
+		vrp:          vrp,
+		stateManager: stateManager,
+	}
+	cm.AddHardRouteConstraint(NewSkillConstraint())
+	cm.AddHardRouteConstraint(NewServiceLoadRouteLevelConstraint(stateManager))
+	cm.AddHardActivityConstraint(NewShipmentPrecedenceConstraint())
+	cm.AddHardActivityConstraint(NewServiceLoadActivityLevelConstraint(stateManager))
+	cm.AddHardActivityConstraint(NewShipmentLoadActivityLevelConstraint(stateManager))
+	cm.AddHardActivityConstraint(NewTimeWindowConstraint(stateManager, vrp.TransportCosts(), vrp.ActivityCosts()))
+	return cm
+}
+
+func (cm *ConstraintManager) StateManager() *state.StateManager {
+	return cm.stateManager
+}
+
+func (cm *ConstraintManager) AddHardRouteConstraint(c HardRouteConstraint) {
+	cm.hardRouteConstraints = append(cm.hardRouteConstraints, c)
+}
+
+func (cm *ConstraintManager) AddHardActivityConstraint(c HardActivityConstraint) {
+	cm.hardActivityConstraints = append(cm.hardActivityConstraints, c)
+}
+
+func (cm *ConstraintManager) AddSoftRouteConstraint(c SoftRouteConstraint) {
+	cm.softRouteConstraints = append(cm.softRouteConstraints, c)
+}
+
+func (cm *ConstraintManager) AddSoftActivityConstraint(c SoftActivityConstraint) {
+	cm.softActivityConstraints = append(cm.softActivityConstraints, c)
+}
+
+// AddConstraint adds c to every kind of constraint it implements. It panics if c implements none.
+func (cm *ConstraintManager) AddConstraint(c any) {
+	added := false
+	if rc, ok := c.(HardRouteConstraint); ok {
+		cm.AddHardRouteConstraint(rc)
+		added = true
+	}
+	if ac, ok := c.(HardActivityConstraint); ok {
+		cm.AddHardActivityConstraint(ac)
+		added = true
+	}
+	if rc, ok := c.(SoftRouteConstraint); ok {
+		cm.AddSoftRouteConstraint(rc)
+		added = true
+	}
+	if ac, ok := c.(SoftActivityConstraint); ok {
+		cm.AddSoftActivityConstraint(ac)
+		added = true
+	}
+	if !added {
+		panic("constraint must implement at least one constraint interface")
+	}
+}
+
+// HardRouteConstraintsFulfilled reports whether all hard route constraints are fulfilled.
+func (cm *ConstraintManager) HardRouteConstraintsFulfilled(iContext *JobInsertionContext) bool {
+	for _, c := range cm.hardRouteConstraints {
+		if !c.Fulfilled(iContext) {
+			return false
+		}
+	}
+	return true
+}
+
+// HardActivityConstraintsFulfilled evaluates all hard activity constraints. NotFulfilledBreak takes precedence
+// over NotFulfilled, since it rules out every later position as well.
+func (cm *ConstraintManager) HardActivityConstraintsFulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) ConstraintsStatus {
+	status := Fulfilled
+	for _, c := range cm.hardActivityConstraints {
+		switch c.Fulfilled(iContext, prevAct, newAct, nextAct, prevActDepTime) {
+		case NotFulfilledBreak:
+			return NotFulfilledBreak
+		case NotFulfilled:
+			status = NotFulfilled
+		}
+	}
+	return status
+}
+
+// SoftRouteCosts returns the sum of the costs of all soft route constraints.
+func (cm *ConstraintManager) SoftRouteCosts(iContext *JobInsertionContext) float64 {
+	costs := 0.
+	for _, c := range cm.softRouteConstraints {
+		costs += c.Costs(iContext)
+	}
+	return costs
+}
+
+// SoftActivityCosts returns the sum of the costs of all soft activity constraints.
+func (cm *ConstraintManager) SoftActivityCosts(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) float64 {
+	costs := 0.
+	for _, c := range cm.softActivityConstraints {
+		costs += c.Costs(iContext, prevAct, newAct, nextAct, prevActDepTime)
+	}
+	return costs
+}
@@ -0,0 +1,168 @@
+package constraint
+
+import (
+	"testing"
+
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixture struct {
+	vrp          *vrp.VehicleRoutingProblem
+	stateManager *state.StateManager
+	vehicle      problem.Vehicle
+	route        *route.VehicleRoute
+	start        *activity.Start
+	end          *activity.End
+}
+
+// newFixture creates a route along the x-axis serving a delivery at 10 and a service at 20 that has to start by 30.
+func newFixture(jobs ...problem.Job) *fixture {
+	tw, _ := activity.NewTimeWindow(0., 30.)
+	delivery := job.NewDeliveryBuilder("delivery").SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+		AddSizeDimension(0, 2).Build()
+	service := job.NewServiceBuilder[*job.Service]("service").SetLocation(problem.NewLocationWithCoordinate(20, 0)).
+		AddSizeDimension(0, 1).SetTimeWindow(tw).Build()
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 3).Build()
+	v := vehicle.NewVehicleBuilder("v").SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		SetLatestArrival(100.).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(delivery).AddJob(service).AddAllJobs(jobs).Build()
+	r := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddDelivery(delivery).AddService(service).Build()
+	sm := state.NewStateManager(p)
+	sm.UpdateRoute(r)
+	start := activity.NewStart(v.StartLocation(), 0., 100.)
+	end := activity.NewEnd(v.EndLocation(), 0., 100.)
+	return &fixture{vrp: p, stateManager: sm, vehicle: v, route: r, start: start, end: end}
+}
+
+func (f *fixture) context(j problem.Job) *JobInsertionContext {
+	return NewJobInsertionContext(f.route, j, f.vehicle, driver.NewNoDriver(), 0.)
+}
+
+func (f *fixture) activities(j problem.Job) []problem.TourActivity {
+	res := make([]problem.TourActivity, 0)
+	for _, act := range f.vrp.JobActivityFactory()(j) {
+		res = append(res, act)
+	}
+	return res
+}
+
+func TestTimeWindowConstraint_ShouldCheckArrivalAtNewAndNextActivity(t *testing.T) {
+	s := job.NewServiceBuilder[*job.Service]("new").SetLocation(problem.NewLocationWithCoordinate(15, 0)).Build()
+	f := newFixture(s)
+	c := NewTimeWindowConstraint(f.stateManager, f.vrp.TransportCosts(), f.vrp.ActivityCosts())
+	acts := f.route.Activities()
+	newAct := f.activities(s)[0]
+
+	// the service is reached at 20 via the new activity, at 30 when passing 10 -> 15 -> 0 -> 20
+	assert.Equal(t, Fulfilled, c.Fulfilled(f.context(s), acts[0], newAct, acts[1], acts[0].EndTime()))
+	assert.Equal(t, Fulfilled, c.Fulfilled(f.context(s), acts[1], newAct, f.end, acts[1].EndTime()))
+
+	newAct.SetTheoreticalLatestOperationStartTime(5.)
+	assert.Equal(t, NotFulfilled, c.Fulfilled(f.context(s), acts[0], newAct, acts[1], acts[0].EndTime()))
+}
+
+func TestTimeWindowConstraint_ShouldBreakIfNextActivityIsLateAnyway(t *testing.T) {
+	s := job.NewServiceBuilder[*job.Service]("new").SetLocation(problem.NewLocationWithCoordinate(15, 0)).Build()
+	f := newFixture(s)
+	c := NewTimeWindowConstraint(f.stateManager, f.vrp.TransportCosts(), f.vrp.ActivityCosts())
+	acts := f.route.Activities()
+
+	assert.Equal(t, NotFulfilledBreak, c.Fulfilled(f.context(s), acts[0], f.activities(s)[0], acts[1], 25.))
+}
+
+func TestServiceLoadConstraints_ShouldCheckCapacity(t *testing.T) {
+	pickup := job.NewPickupBuilder("pickup").SetLocation(problem.NewLocationWithCoordinate(5, 0)).AddSizeDimension(0, 2).Build()
+	delivery := job.NewDeliveryBuilder("new").SetLocation(problem.NewLocationWithCoordinate(5, 0)).AddSizeDimension(0, 1).Build()
+	f := newFixture(pickup, delivery)
+	c := NewServiceLoadActivityLevelConstraint(f.stateManager)
+	acts := f.route.Activities()
+
+	// the load is 2 at the start, 0 after the delivery and 1 after the service
+	assert.Equal(t, NotFulfilled, c.Fulfilled(f.context(pickup), f.start, f.activities(pickup)[0], acts[0], 0.))
+	assert.Equal(t, Fulfilled, c.Fulfilled(f.context(pickup), acts[0], f.activities(pickup)[0], acts[1], 0.))
+	assert.Equal(t, Fulfilled, c.Fulfilled(f.context(delivery), f.start, f.activities(delivery)[0], acts[0], 0.))
+
+	routeLevel := NewServiceLoadRouteLevelConstraint(f.stateManager)
+	assert.True(t, routeLevel.Fulfilled(f.context(delivery)))
+	big := job.NewDeliveryBuilder("big").SetLocation(problem.NewLocationWithCoordinate(5, 0)).AddSizeDimension(0, 2).Build()
+	assert.False(t, routeLevel.Fulfilled(f.context(big)))
+}
+
+func TestShipmentConstraints_DeliveryMustFollowPickupAndFit(t *testing.T) {
+	shipment := job.NewShipmentBuilder("shipment").
+		SetPickupLocation(problem.NewLocationWithCoordinate(5, 0)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(25, 0)).
+		AddSizeDimension(0, 3).Build()
+	f := newFixture(shipment)
+	load := NewShipmentLoadActivityLevelConstraint(f.stateManager)
+	precedence := NewShipmentPrecedenceConstraint()
+	acts := f.route.Activities()
+	shipmentActs := f.activities(shipment)
+	iContext := f.context(shipment)
+
+	// the vehicle is full at the start
+	assert.Equal(t, NotFulfilled, load.Fulfilled(iContext, f.start, shipmentActs[0], acts[0], 0.))
+	assert.Equal(t, Fulfilled, load.Fulfilled(iContext, acts[0], shipmentActs[0], acts[1], 0.))
+	// carrying the shipment past the service exceeds the capacity
+	assert.Equal(t, NotFulfilledBreak, load.Fulfilled(iContext, acts[1], shipmentActs[1], f.end, 0.))
+
+	iContext.SetActivityContext(NewActivityContext(1, 0., 0.))
+	assert.Equal(t, NotFulfilled, precedence.Fulfilled(iContext, acts[0], shipmentActs[1], acts[1], 0.))
+	iContext.SetRelatedActivityContext(NewActivityContext(2, 0., 0.))
+	assert.Equal(t, NotFulfilled, precedence.Fulfilled(iContext, acts[0], shipmentActs[1], acts[1], 0.))
+	iContext.SetRelatedActivityContext(NewActivityContext(1, 0., 0.))
+	assert.Equal(t, Fulfilled, precedence.Fulfilled(iContext, acts[0], shipmentActs[1], acts[1], 0.))
+}
+
+func TestSkillConstraint_VehicleMustHaveAllRequiredSkills(t *testing.T) {
+	s := job.NewServiceBuilder[*job.Service]("new").SetLocation(problem.NewLocationWithCoordinate(5, 0)).
+		AddRequiredSkill("Drill").Build()
+	f := newFixture(s)
+	c := NewSkillConstraint()
+
+	assert.False(t, c.Fulfilled(f.context(s)))
+
+	t1 := vehicle.NewVehicleTypeBuilder("type").Build()
+	v := vehicle.NewVehicleBuilder("skilled").SetType(t1).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		AddSkill("drill").Build()
+	assert.True(t, c.Fulfilled(NewJobInsertionContext(f.route, s, v, driver.NewNoDriver(), 0.)))
+}
+
+type fixedStatus struct {
+	status ConstraintsStatus
+	costs  float64
+}
+
+func (c *fixedStatus) Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity, prevActDepTime float64) ConstraintsStatus {
+	return c.status
+}
+
+func (c *fixedStatus) Costs(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity, prevActDepTime float64) float64 {
+	return c.costs
+}
+
+func TestConstraintManager_NotFulfilledBreakMustTakePrecedence(t *testing.T) {
+	s := job.NewServiceBuilder[*job.Service]("new").SetLocation(problem.NewLocationWithCoordinate(5, 0)).Build()
+	f := newFixture(s)
+	cm := NewConstraintManager(f.vrp, f.stateManager)
+	acts := f.route.Activities()
+	newAct := f.activities(s)[0]
+
+	assert.Equal(t, Fulfilled, cm.HardActivityConstraintsFulfilled(f.context(s), acts[0], newAct, acts[1], acts[0].EndTime()))
+	cm.AddConstraint(&fixedStatus{status: NotFulfilled, costs: 2.})
+	cm.AddConstraint(&fixedStatus{status: NotFulfilledBreak, costs: 3.})
+	cm.AddConstraint(&fixedStatus{status: NotFulfilled})
+	assert.Equal(t, NotFulfilledBreak, cm.HardActivityConstraintsFulfilled(f.context(s), acts[0], newAct, acts[1], acts[0].EndTime()))
+	assert.Equal(t, 5., cm.SoftActivityCosts(f.context(s), acts[0], newAct, acts[1], acts[0].EndTime()))
+	assert.Panics(t, func() { cm.AddConstraint(struct{}{}) })
+}
@@ -0,0 +1,116 @@
+package constraint
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+)
+
+// JobInsertionContext describes the insertion of a job into a route that is evaluated by the constraints.
+type JobInsertionContext struct {
+	route                  *route.VehicleRoute
+	job                    problem.Job
+	newVehicle             problem.Vehicle
+	newDriver              problem.Driver
+	newDepTime             float64
+	associatedActivities   []problem.TourActivity
+	activityContext        *ActivityContext
+	relatedActivityContext *ActivityContext
+}
+
+func NewJobInsertionContext(vehicleRoute *route.VehicleRoute, job problem.Job, newVehicle problem.Vehicle,
+	newDriver problem.Driver, newDepTime float64) *JobInsertionContext {
+	return &JobInsertionContext{
+		route:      vehicleRoute,
+		job:        job,
+		newVehicle: newVehicle,
+		newDriver:  newDriver,
+		newDepTime: newDepTime,
+	}
+}
+
+func (c *JobInsertionContext) Route() *route.VehicleRoute {
+	return c.route
+}
+
+func (c *JobInsertionContext) Job() problem.Job {
+	return c.job
+}
+
+func (c *JobInsertionContext) NewVehicle() problem.Vehicle {
+	return c.newVehicle
+}
+
+func (c *JobInsertionContext) NewDriver() problem.Driver {
+	return c.newDriver
+}
+
+func (c *JobInsertionContext) NewDepTime() float64 {
+	return c.newDepTime
+}
+
+// AssociatedActivities returns the activities of the job to be inserted.
+func (c *JobInsertionContext) AssociatedActivities() []problem.TourActivity {
+	return c.associatedActivities
+}
+
+func (c *JobInsertionContext) SetAssociatedActivities(acts []problem.TourActivity) {
+	c.associatedActivities = acts
+}
+
+// ActivityContext returns the context of the activity currently evaluated.
+func (c *JobInsertionContext) ActivityContext() *ActivityContext {
+	return c.activityContext
+}
+
+func (c *JobInsertionContext) SetActivityContext(activityContext *ActivityContext) {
+	c.activityContext = activityContext
+}
+
+// RelatedActivityContext returns the context of an activity of the same job that has already been placed,
+// e.g. the pickup of a shipment while its delivery is evaluated. It is nil if there is none.
+func (c *JobInsertionContext) RelatedActivityContext() *ActivityContext {
+	return c.relatedActivityContext
+}
+
+func (c *JobInsertionContext) SetRelatedActivityContext(relatedActivityContext *ActivityContext) {
+	c.relatedActivityContext = relatedActivityContext
+}
+
+// ActivityContext holds the insertion index of an activity as well as its arrival and end time at that index.
+type ActivityContext struct {
+	insertionIndex int
+	arrivalTime    float64
+	endTime        float64
+}
+
+func NewActivityContext(insertionIndex int, arrivalTime, endTime float64) *ActivityContext {
+	return &ActivityContext{
+		insertionIndex: insertionIndex,
+		arrivalTime:    arrivalTime,
+		endTime:        endTime,
+	}
+}
+
+func (c *ActivityContext) InsertionIndex() int {
+	return c.insertionIndex
+}
+
+func (c *ActivityContext) SetInsertionIndex(insertionIndex int) {
+	c.insertionIndex = insertionIndex
+}
+
+func (c *ActivityContext) ArrivalTime() float64 {
+	return c.arrivalTime
+}
+
+func (c *ActivityContext) SetArrivalTime(arrivalTime float64) {
+	c.arrivalTime = arrivalTime
+}
+
+func (c *ActivityContext) EndTime() float64 {
+	return c.endTime
+}
+
+func (c *ActivityContext) SetEndTime(endTime float64) {
+	c.endTime = endTime
+}
@@ -0,0 +1,117 @@
+package constraint
+
+import (
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route/activity"
+)
+
+var _ HardRouteConstraint = (*ServiceLoadRouteLevelConstraint)(nil)
+
+// ServiceLoadRouteLevelConstraint checks whether the vehicle can carry a job at all. Deliveries of services are
+// loaded at the depot and hence add to the load at the beginning of the route, pickups and services add to the
+// load at its end.
+type ServiceLoadRouteLevelConstraint struct {
+	stateManager *state.StateManager
+}
+
+func NewServiceLoadRouteLevelConstraint(stateManager *state.StateManager) *ServiceLoadRouteLevelConstraint {
+	return &ServiceLoadRouteLevelConstraint{
+		stateManager: stateManager,
+	}
+}
+
+func (c *ServiceLoadRouteLevelConstraint) Fulfilled(iContext *JobInsertionContext) bool {
+	job := iContext.Job()
+	capacity := iContext.NewVehicle().Type().CapacityDimensions()
+	switch {
+	case job.JobType().IsShipment():
+		return job.Size().IsLessOrEqual(capacity)
+	case job.JobType().IsDelivery():
+		return problem.AddUp(c.stateManager.LoadAtBeginning(iContext.Route()), job.Size()).IsLessOrEqual(capacity)
+	default:
+		return problem.AddUp(c.stateManager.LoadAtEnd(iContext.Route()), job.Size()).IsLessOrEqual(capacity)
+	}
+}
+
+var _ HardActivityConstraint = (*ServiceLoadActivityLevelConstraint)(nil)
+
+// ServiceLoadActivityLevelConstraint checks the capacity when inserting the activity of a service. A pickup or
+// service increases the load from its position to the end, a delivery the load from the beginning up to its
+// position. Since the maximum load up to a position never decreases along the route, a delivery that does not
+// fit at a position does not fit at any later one either.
+type ServiceLoadActivityLevelConstraint struct {
+	stateManager *state.StateManager
+}
+
+func NewServiceLoadActivityLevelConstraint(stateManager *state.StateManager) *ServiceLoadActivityLevelConstraint {
+	return &ServiceLoadActivityLevelConstraint{
+		stateManager: stateManager,
+	}
+}
+
+func (c *ServiceLoadActivityLevelConstraint) Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) ConstraintsStatus {
+	capacity := iContext.NewVehicle().Type().CapacityDimensions()
+	_, isStart := prevAct.(*activity.Start)
+	switch newAct.(type) {
+	case *activity.PickupService, *activity.ServiceActivity:
+		futureMaxLoad := c.stateManager.MaxLoad(iContext.Route())
+		if !isStart {
+			futureMaxLoad = c.stateManager.FutureMaxLoad(prevAct)
+		}
+		if !problem.AddUp(futureMaxLoad, newAct.Size()).IsLessOrEqual(capacity) {
+			return NotFulfilled
+		}
+	case *activity.DeliverService:
+		pastMaxLoad := c.stateManager.LoadAtBeginning(iContext.Route())
+		if !isStart {
+			pastMaxLoad = c.stateManager.PastMaxLoad(prevAct)
+		}
+		if !problem.AddUp(pastMaxLoad, problem.Invert(newAct.Size())).IsLessOrEqual(capacity) {
+			return NotFulfilledBreak
+		}
+	}
+	return Fulfilled
+}
+
+var _ HardActivityConstraint = (*ShipmentLoadActivityLevelConstraint)(nil)
+
+// ShipmentLoadActivityLevelConstraint checks the capacity when inserting the activities of a shipment. The
+// shipment is carried from its pickup to its delivery, i.e. the load after every activity in between increases
+// by its size. Positions of the delivery are expected to be evaluated in the order of the route, thus the load
+// after each of these activities is checked as the activity preceding the delivery.
+type ShipmentLoadActivityLevelConstraint struct {
+	stateManager *state.StateManager
+}
+
+func NewShipmentLoadActivityLevelConstraint(stateManager *state.StateManager) *ShipmentLoadActivityLevelConstraint {
+	return &ShipmentLoadActivityLevelConstraint{
+		stateManager: stateManager,
+	}
+}
+
+func (c *ShipmentLoadActivityLevelConstraint) Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) ConstraintsStatus {
+	var size *problem.Capacity
+	switch newAct.(type) {
+	case *activity.PickupShipment:
+		size = newAct.Size()
+	case *activity.DeliverShipment:
+		size = problem.Invert(newAct.Size())
+	default:
+		return Fulfilled
+	}
+	loadAtPrevAct := c.stateManager.Load(prevAct)
+	if _, ok := prevAct.(*activity.Start); ok {
+		loadAtPrevAct = c.stateManager.LoadAtBeginning(iContext.Route())
+	}
+	if problem.AddUp(loadAtPrevAct, size).IsLessOrEqual(iContext.NewVehicle().Type().CapacityDimensions()) {
+		return Fulfilled
+	}
+	// the shipment would be carried past prevAct when delivering later
+	if _, ok := newAct.(*activity.DeliverShipment); ok {
+		return NotFulfilledBreak
+	}
+	return NotFulfilled
+}
@@ -0,0 +1,35 @@
+package constraint
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route/activity"
+)
+
+var _ HardActivityConstraint = (*ShipmentPrecedenceConstraint)(nil)
+
+// ShipmentPrecedenceConstraint ensures that the delivery of a shipment is not inserted before its pickup. The
+// position of the pickup has to be provided as the related activity context of the insertion.
+type ShipmentPrecedenceConstraint struct{}
+
+func NewShipmentPrecedenceConstraint() *ShipmentPrecedenceConstraint {
+	return &ShipmentPrecedenceConstraint{}
+}
+
+func (c *ShipmentPrecedenceConstraint) Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) ConstraintsStatus {
+	delivery, ok := newAct.(*activity.DeliverShipment)
+	if !ok {
+		return Fulfilled
+	}
+	if pickup, ok := nextAct.(*activity.PickupShipment); ok && pickup.Job() == delivery.Job() {
+		return NotFulfilled
+	}
+	pickupContext := iContext.RelatedActivityContext()
+	if pickupContext == nil {
+		return NotFulfilled
+	}
+	if ac := iContext.ActivityContext(); ac != nil && ac.InsertionIndex() < pickupContext.InsertionIndex() {
+		return NotFulfilled
+	}
+	return Fulfilled
+}
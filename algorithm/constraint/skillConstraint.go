@@ -0,0 +1,24 @@
+package constraint
+
+var _ HardRouteConstraint = (*SkillConstraint)(nil)
+
+// SkillConstraint ensures that the vehicle has all skills required by the job.
+type SkillConstraint struct{}
+
+func NewSkillConstraint() *SkillConstraint {
+	return &SkillConstraint{}
+}
+
+func (c *SkillConstraint) Fulfilled(iContext *JobInsertionContext) bool {
+	required := iContext.Job().RequiredSkills()
+	if required == nil {
+		return true
+	}
+	skills := iContext.NewVehicle().Skills()
+	for _, skill := range required.Values() {
+		if skills == nil || !skills.Contains(skill) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package constraint
+
+import (
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/solution/route/activity"
+	"math"
+)
+
+var _ HardActivityConstraint = (*TimeWindowConstraint)(nil)
+
+// TimeWindowConstraint ensures that newAct starts within its time window and that the vehicle still arrives at
+// nextAct in time, i.e. not later than the latest operation start time cached by the StateManager or, if nextAct
+// is the end of the route, not later than the latest arrival of the vehicle.
+type TimeWindowConstraint struct {
+	stateManager   *state.StateManager
+	transportCosts cost.VehicleRoutingTransportCosts
+	activityCosts  cost.VehicleRoutingActivityCosts
+}
+
+func NewTimeWindowConstraint(stateManager *state.StateManager, transportCosts cost.VehicleRoutingTransportCosts,
+	activityCosts cost.VehicleRoutingActivityCosts) *TimeWindowConstraint {
+	return &TimeWindowConstraint{
+		stateManager:   stateManager,
+		transportCosts: transportCosts,
+		activityCosts:  activityCosts,
+	}
+}
+
+func (c *TimeWindowConstraint) Fulfilled(iContext *JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) ConstraintsStatus {
+	v, d := iContext.NewVehicle(), iContext.NewDriver()
+	latestVehicleArrival := v.LatestArrival()
+	_, isEnd := nextAct.(*activity.End)
+	// a vehicle not returning to its depot ends its route right after the last activity
+	isOpenEnd := isEnd && !v.IsReturnToDepot()
+	latestArrTimeAtNextAct := latestVehicleArrival
+	nextActLocation := nextAct.Location()
+	if isOpenEnd {
+		nextActLocation = newAct.Location()
+	}
+	if !isEnd {
+		latestArrTimeAtNextAct = c.stateManager.LatestOperationStartTime(nextAct)
+	}
+
+	// the vehicle has to be back before any of the activities can start
+	if latestVehicleArrival < prevAct.TheoreticalEarliestOperationStartTime() ||
+		latestVehicleArrival < newAct.TheoreticalEarliestOperationStartTime() ||
+		latestVehicleArrival < nextAct.TheoreticalEarliestOperationStartTime() {
+		return NotFulfilledBreak
+	}
+	// newAct has to start before prevAct could start, so it cannot follow prevAct or any later activity
+	if newAct.TheoreticalLatestOperationStartTime() < prevAct.TheoreticalEarliestOperationStartTime() {
+		return NotFulfilledBreak
+	}
+	// the vehicle is already late at nextAct without newAct
+	arrTimeAtNextOnDirectRoute := prevActDepTime + c.transportCosts.TransportTime(prevAct.Location(), nextActLocation, prevActDepTime, d, v)
+	if arrTimeAtNextOnDirectRoute > latestArrTimeAtNextAct {
+		return NotFulfilledBreak
+	}
+	// newAct cannot start before nextAct has to start
+	if newAct.TheoreticalEarliestOperationStartTime() > nextAct.TheoreticalLatestOperationStartTime() {
+		return NotFulfilled
+	}
+
+	arrTimeAtNewAct := prevActDepTime + c.transportCosts.TransportTime(prevAct.Location(), newAct.Location(), prevActDepTime, d, v)
+	if arrTimeAtNewAct > newAct.TheoreticalLatestOperationStartTime() {
+		return NotFulfilled
+	}
+	if isOpenEnd {
+		return Fulfilled
+	}
+	endTimeAtNewAct := math.Max(arrTimeAtNewAct, newAct.TheoreticalEarliestOperationStartTime()) +
+		c.activityCosts.ActivityDuration(newAct, arrTimeAtNewAct, d, v)
+	arrTimeAtNextAct := endTimeAtNewAct + c.transportCosts.TransportTime(newAct.Location(), nextActLocation, endTimeAtNewAct, d, v)
+	if arrTimeAtNextAct > latestArrTimeAtNextAct {
+		return NotFulfilled
+	}
+	return Fulfilled
+}
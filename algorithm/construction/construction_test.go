@@ -0,0 +1,196 @@
+package construction
+
+import (
+	"fmt"
+	"testing"
+
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/objective"
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBestInsertion(p *vrp.VehicleRoutingProblem) recreate.InsertionStrategy {
+	sm := state.NewStateManager(p)
+	return recreate.NewBestInsertion(p, sm, constraint.NewConstraintManager(p, sm))
+}
+
+// newSavingsConstruction creates a savings construction whose insertion shares its ConstraintManager, to which
+// constraints are added.
+func newSavingsConstruction(p *vrp.VehicleRoutingProblem, constraints ...any) *SavingsConstruction {
+	sm := state.NewStateManager(p)
+	cm := constraint.NewConstraintManager(p, sm)
+	for _, c := range constraints {
+		cm.AddConstraint(c)
+	}
+	return NewSavingsConstruction(recreate.NewBestInsertion(p, sm, cm), cm, objective.NewDefaultObjectiveFunction(p))
+}
+
+func newVehicle(id string, capacity int) problem.Vehicle {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, capacity).Build()
+	return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+}
+
+func newService(id string, x, y float64) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).SetLocation(problem.NewLocationWithCoordinate(x, y)).
+		AddSizeDimension(0, 1).Build()
+}
+
+func servedJobs(s *solution.VehicleRoutingProblemSolution) int {
+	res := 0
+	for _, r := range s.Routes() {
+		res += r.TourActivities().JobSize()
+	}
+	return res
+}
+
+func TestInsertionInitialSolutionFactory_ShouldKeepJobsOfInitialRoutes(t *testing.T) {
+	v := newVehicle("v", 10)
+	fixed := newService("fixed", 50, 0)
+	initialRoute := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(fixed).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(newService("s1", 10, 0)).AddJob(newService("s2", 20, 0)).
+		AddInitialVehicleRoute(initialRoute).Build()
+
+	s := NewInsertionInitialSolutionFactory(newBestInsertion(p), objective.NewDefaultObjectiveFunction(p)).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	assert.Len(t, s.Routes(), 1)
+	assert.True(t, s.Routes()[0].TourActivities().ServesJob(fixed))
+	assert.Equal(t, 3, servedJobs(s))
+}
+
+func TestSavingsConstruction_ShouldMergeRoutesOfNeighbouringServices(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(newVehicle("v", 10))
+	for i := 0; i < 3; i++ {
+		b.AddJob(newService(fmt.Sprintf("east%d", i), 100.+float64(i), 0))
+		b.AddJob(newService(fmt.Sprintf("west%d", i), -100.-float64(i), 0))
+	}
+	p := b.Build()
+
+	s := newSavingsConstruction(p).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	assert.Equal(t, 6, servedJobs(s))
+	// east and west are served by separate tours, merging them saves nothing
+	assert.Len(t, s.Routes(), 2)
+	for _, r := range s.Routes() {
+		assert.Equal(t, 3, r.TourActivities().JobSize())
+	}
+}
+
+func TestSavingsConstruction_ShouldRespectCapacities(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(newVehicle("v", 2))
+	for i := 0; i < 5; i++ {
+		b.AddJob(newService(fmt.Sprintf("s%d", i), 100.+float64(i), 0))
+	}
+	p := b.Build()
+
+	s := newSavingsConstruction(p).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	assert.Equal(t, 5, servedJobs(s))
+	assert.Len(t, s.Routes(), 3)
+	for _, r := range s.Routes() {
+		assert.LessOrEqual(t, r.TourActivities().JobSize(), 2)
+	}
+}
+
+func TestSavingsConstruction_ShouldInsertShipmentsAndRespectInitialRoutes(t *testing.T) {
+	v1, v2 := newVehicle("v1", 10), newVehicle("v2", 10)
+	fixed := newService("fixed", 0, 50)
+	shipment := job.NewShipmentBuilder("shipment").AddSizeDimension(0, 1).
+		SetPickupLocation(problem.NewLocationWithCoordinate(10, 0)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(20, 0)).Build()
+	initialRoute := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddService(fixed).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).SetFleetSize(vrp.Finite).AddVehicle(v1).AddVehicle(v2).
+		AddJob(newService("s1", 30, 0)).AddJob(newService("s2", 40, 0)).AddJob(shipment).
+		AddInitialVehicleRoute(initialRoute).Build()
+
+	s := newSavingsConstruction(p).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	assert.Equal(t, 4, servedJobs(s))
+	assert.Len(t, s.Routes(), 2)
+	for _, r := range s.Routes() {
+		if r.Vehicle() == v1 {
+			assert.True(t, r.TourActivities().ServesJob(fixed))
+		} else {
+			assert.True(t, r.TourActivities().ServesJob(shipment))
+		}
+	}
+	assert.Equal(t, objective.NewDefaultObjectiveFunction(p).Costs(s), s.Cost())
+}
+
+// maxJobsPerRoute is a hard route constraint limiting the number of jobs of a route.
+type maxJobsPerRoute struct {
+	max int
+}
+
+func (c *maxJobsPerRoute) Fulfilled(iContext *constraint.JobInsertionContext) bool {
+	return iContext.Route().TourActivities().JobSize() < c.max
+}
+
+// firstInRoute is a hard activity constraint requiring the activity of job to be the first of its route.
+type firstInRoute struct {
+	job problem.Job
+}
+
+func (c *firstInRoute) Fulfilled(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) constraint.ConstraintsStatus {
+	if ja, ok := newAct.(problem.JobActivity); ok && ja.Job() == c.job {
+		if _, isJobAct := prevAct.(problem.JobActivity); isJobAct {
+			return constraint.NotFulfilled
+		}
+	}
+	if ja, ok := nextAct.(problem.JobActivity); ok && ja.Job() == c.job {
+		return constraint.NotFulfilled
+	}
+	return constraint.Fulfilled
+}
+
+func TestSavingsConstruction_ShouldRespectHardRouteConstraints(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(newVehicle("v", 10))
+	for i := 0; i < 6; i++ {
+		b.AddJob(newService(fmt.Sprintf("s%d", i), 100.+float64(i), 0))
+	}
+	p := b.Build()
+
+	s := newSavingsConstruction(p, &maxJobsPerRoute{max: 2}).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	assert.Len(t, s.Routes(), 3)
+	for _, r := range s.Routes() {
+		assert.Equal(t, 2, r.TourActivities().JobSize())
+	}
+}
+
+func TestSavingsConstruction_ShouldRespectHardActivityConstraints(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(newVehicle("v", 10))
+	services := make([]*job.Service, 0)
+	for i := 0; i < 4; i++ {
+		services = append(services, newService(fmt.Sprintf("s%d", i), 100.+float64(i), 0))
+		b.AddJob(services[i])
+	}
+	p := b.Build()
+
+	s := newSavingsConstruction(p, &firstInRoute{job: services[2]}).CreateSolution(p)
+
+	assert.Empty(t, s.UnassignedJobs())
+	for _, r := range s.Routes() {
+		for k, act := range r.Activities() {
+			if act.(problem.JobActivity).Job() == services[2] {
+				assert.Equal(t, 0, k)
+			}
+		}
+	}
+}
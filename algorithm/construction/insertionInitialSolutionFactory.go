@@ -0,0 +1,47 @@
+package construction
+
+import (
+	"gsprit/algorithm"
+	"gsprit/algorithm/recreate"
+	"gsprit/problem"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ algorithm.InitialSolutionFactory = (*InsertionInitialSolutionFactory)(nil)
+
+// InsertionInitialSolutionFactory creates an initial solution by inserting all jobs of a problem with an
+// insertion strategy, e.g. best or regret insertion. It starts from the initial vehicle routes of the problem,
+// i.e. jobs fixed in these routes stay where they are.
+type InsertionInitialSolutionFactory struct {
+	insertion         recreate.InsertionStrategy
+	objectiveFunction solution.SolutionCostCalculator
+}
+
+func NewInsertionInitialSolutionFactory(insertion recreate.InsertionStrategy, objectiveFunction solution.SolutionCostCalculator) *InsertionInitialSolutionFactory {
+	return &InsertionInitialSolutionFactory{
+		insertion:         insertion,
+		objectiveFunction: objectiveFunction,
+	}
+}
+
+func (f *InsertionInitialSolutionFactory) CreateSolution(vrp *vrp.VehicleRoutingProblem) *solution.VehicleRoutingProblemSolution {
+	vehicleRoutes := vrp.InitialVehicleRoutes()
+	badJobs := f.insertion.InsertJobs(&vehicleRoutes, vrp.JobsOrderedByIndex())
+	return newSolution(vehicleRoutes, badJobs, f.objectiveFunction)
+}
+
+// newSolution creates a solution of the non-empty routes in vehicleRoutes and calculates its costs.
+func newSolution(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job,
+	objectiveFunction solution.SolutionCostCalculator) *solution.VehicleRoutingProblemSolution {
+	nonEmptyRoutes := make([]*route.VehicleRoute, 0, len(vehicleRoutes))
+	for _, vr := range vehicleRoutes {
+		if !vr.IsEmpty() {
+			nonEmptyRoutes = append(nonEmptyRoutes, vr)
+		}
+	}
+	res := solution.NewVehicleRoutingProblemSolutionWithJobs(nonEmptyRoutes, unassignedJobs, 0.)
+	res.SetCost(objectiveFunction.Costs(res))
+	return res
+}
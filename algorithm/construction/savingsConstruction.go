@@ -0,0 +1,300 @@
+package construction
+
+import (
+	"gsprit/algorithm"
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/recreate"
+	"gsprit/problem"
+	"gsprit/problem/driver"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vrp"
+	"math"
+	"sort"
+)
+
+var _ algorithm.InitialSolutionFactory = (*SavingsConstruction)(nil)
+
+// SavingsConstruction creates an initial solution with the parallel savings algorithm of Clarke and Wright.
+// Every service starts in a route of its own. Then, in the order of decreasing savings
+//
+//	s(i,j) = c(i,depot) + c(depot,j) - c(i,j) + fixed costs of the vehicle
+//
+// the route ending with i and the route starting with j are merged as long as the merged route is feasible.
+// Savings are calculated for the first available vehicle, the merged routes are assigned to the available
+// vehicles afterwards. Every route, merged or not, has to fulfil the hard route and activity constraints of the
+// given ConstraintManager, which should be the one of the insertion strategy.
+//
+// Jobs the savings algorithm cannot handle, i.e. shipments and services that fit into none of the routes, are
+// inserted afterwards with the given insertion strategy, which considers all constraints. Jobs fixed in the
+// initial vehicle routes of the problem stay where they are.
+type SavingsConstruction struct {
+	insertion         recreate.InsertionStrategy
+	constraintManager *constraint.ConstraintManager
+	objectiveFunction solution.SolutionCostCalculator
+}
+
+func NewSavingsConstruction(insertion recreate.InsertionStrategy, constraintManager *constraint.ConstraintManager,
+	objectiveFunction solution.SolutionCostCalculator) *SavingsConstruction {
+	return &SavingsConstruction{
+		insertion:         insertion,
+		constraintManager: constraintManager,
+		objectiveFunction: objectiveFunction,
+	}
+}
+
+type saving struct {
+	from, to problem.Job
+	value    float64
+}
+
+// savingsRoute is a sequence of services together with the activities serving them.
+type savingsRoute struct {
+	jobs []problem.Job
+	acts []problem.TourActivity
+}
+
+func (c *SavingsConstruction) CreateSolution(vrp *vrp.VehicleRoutingProblem) *solution.VehicleRoutingProblemSolution {
+	vehicleRoutes := vrp.InitialVehicleRoutes()
+	vehicles := availableVehicles(vrp, vehicleRoutes)
+	leftJobs := make([]problem.Job, 0)
+	if len(vehicles) == 0 {
+		leftJobs = vrp.JobsOrderedByIndex()
+	} else {
+		checker := newRouteChecker(vrp, c.constraintManager)
+		reference := vehicles[0]
+		routeOf := make(map[problem.Job]*savingsRoute)
+		services := make([]problem.Job, 0)
+		for _, job := range vrp.JobsOrderedByIndex() {
+			service, ok := job.(problem.Service)
+			if !ok || job.JobType().IsBreak() || service.Location() == nil {
+				leftJobs = append(leftJobs, job)
+				continue
+			}
+			r := &savingsRoute{jobs: []problem.Job{job}, acts: []problem.TourActivity{vrp.JobActivityFactory()(job)[0]}}
+			if !checker.isFeasible(r, reference) {
+				leftJobs = append(leftJobs, job)
+				continue
+			}
+			routeOf[job] = r
+			services = append(services, job)
+		}
+
+		for _, s := range c.savings(vrp, services, reference) {
+			from, to := routeOf[s.from], routeOf[s.to]
+			if from == to || from.jobs[len(from.jobs)-1] != s.from || to.jobs[0] != s.to {
+				continue
+			}
+			merged := &savingsRoute{
+				jobs: append(append([]problem.Job{}, from.jobs...), to.jobs...),
+				acts: append(append([]problem.TourActivity{}, from.acts...), to.acts...),
+			}
+			if !checker.isFeasible(merged, reference) {
+				continue
+			}
+			for _, job := range merged.jobs {
+				routeOf[job] = merged
+			}
+		}
+
+		vehicleRoutes, leftJobs = c.assignVehicles(isFiniteFleet(vrp), checker, services, routeOf, vehicles, vehicleRoutes, leftJobs)
+	}
+	badJobs := c.insertion.InsertJobs(&vehicleRoutes, leftJobs)
+	return newSolution(vehicleRoutes, badJobs, c.objectiveFunction)
+}
+
+// savings returns the positive savings of all pairs of services in decreasing order.
+func (c *SavingsConstruction) savings(vrp *vrp.VehicleRoutingProblem, services []problem.Job, v problem.Vehicle) []saving {
+	costs := vrp.TransportCosts()
+	d := driver.NewNoDriver()
+	location := func(job problem.Job) *problem.Location {
+		return job.(problem.Service).Location()
+	}
+	// every merge saves a vehicle
+	fixedCosts := v.Type().VehicleCostParams().Fix()
+	res := make([]saving, 0)
+	for _, i := range services {
+		toDepot := fixedCosts
+		if v.IsReturnToDepot() {
+			toDepot += costs.TransportCost(location(i), v.EndLocation(), 0., d, v)
+		}
+		for _, j := range services {
+			if i == j {
+				continue
+			}
+			value := toDepot + costs.TransportCost(v.StartLocation(), location(j), 0., d, v) -
+				costs.TransportCost(location(i), location(j), 0., d, v)
+			if value > 0. {
+				res = append(res, saving{from: i, to: j, value: value})
+			}
+		}
+	}
+	sort.SliceStable(res, func(a, b int) bool {
+		return res[a].value > res[b].value
+	})
+	return res
+}
+
+// assignVehicles turns the routes of the savings algorithm into vehicle routes, largest routes first. With a
+// finite fleet every vehicle serves at most one route and the jobs of routes without a feasible vehicle are
+// left for the insertion.
+func (c *SavingsConstruction) assignVehicles(finiteFleet bool, checker *routeChecker, services []problem.Job,
+	routeOf map[problem.Job]*savingsRoute, vehicles []problem.Vehicle, vehicleRoutes []*route.VehicleRoute,
+	leftJobs []problem.Job) ([]*route.VehicleRoute, []problem.Job) {
+	routes := make([]*savingsRoute, 0)
+	seen := make(map[*savingsRoute]bool)
+	for _, job := range services {
+		if r := routeOf[job]; !seen[r] {
+			seen[r] = true
+			routes = append(routes, r)
+		}
+	}
+	sort.SliceStable(routes, func(a, b int) bool {
+		return len(routes[a].jobs) > len(routes[b].jobs)
+	})
+	used := make(map[problem.Vehicle]bool)
+	for _, r := range routes {
+		var selected problem.Vehicle
+		for _, v := range vehicles {
+			if used[v] || !checker.isFeasible(r, v) {
+				continue
+			}
+			selected = v
+			break
+		}
+		if selected == nil {
+			leftJobs = append(leftJobs, r.jobs...)
+			continue
+		}
+		if finiteFleet {
+			used[selected] = true
+		}
+		// isFeasible has set the time windows the activities are served in
+		vr := route.NewVehicleRouteBuilder(selected, driver.NewNoDriver()).
+			SetDepartureTime(selected.EarliestDeparture()).
+			Build()
+		for _, act := range r.acts {
+			vr.TourActivities().AddActivityToEnd(act)
+		}
+		if !selected.IsReturnToDepot() {
+			vr.End().SetLocation(r.acts[len(r.acts)-1].Location())
+		}
+		vehicleRoutes = append(vehicleRoutes, vr)
+	}
+	return vehicleRoutes, leftJobs
+}
+
+// availableVehicles returns the vehicles that can open new routes ordered by their index. With a finite fleet
+// vehicles serving one of vehicleRoutes are not available.
+func availableVehicles(p *vrp.VehicleRoutingProblem, vehicleRoutes []*route.VehicleRoute) []problem.Vehicle {
+	used := make(map[problem.Vehicle]bool)
+	if isFiniteFleet(p) {
+		for _, vr := range vehicleRoutes {
+			used[vr.Vehicle()] = true
+		}
+	}
+	res := make([]problem.Vehicle, 0)
+	for _, v := range p.Vehicles() {
+		if !used[v] {
+			res = append(res, v)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Index() < res[j].Index()
+	})
+	return res
+}
+
+func isFiniteFleet(p *vrp.VehicleRoutingProblem) bool {
+	return p.FleetSize() == vrp.Finite
+}
+
+// routeChecker checks whether a sequence of activities can be served by a vehicle.
+type routeChecker struct {
+	vrp               *vrp.VehicleRoutingProblem
+	constraintManager *constraint.ConstraintManager
+}
+
+func newRouteChecker(vrp *vrp.VehicleRoutingProblem, constraintManager *constraint.ConstraintManager) *routeChecker {
+	return &routeChecker{
+		vrp:               vrp,
+		constraintManager: constraintManager,
+	}
+}
+
+// isFeasible reports whether v can serve r without violating its capacity, a time window, its latest arrival or
+// a hard constraint. Each activity is served in the first time window it can be reached in, which is set as its
+// theoretical earliest and latest operation start time.
+func (c *routeChecker) isFeasible(r *savingsRoute, v problem.Vehicle) bool {
+	transportCosts, activityCosts := c.vrp.TransportCosts(), c.vrp.ActivityCosts()
+	d := driver.NewNoDriver()
+	capacity := v.Type().CapacityDimensions()
+	load := problem.NewCapacity(nil)
+	for _, act := range r.acts {
+		if _, ok := act.(*activity.DeliverService); ok {
+			load = problem.AddUp(load, problem.Invert(act.Size()))
+		}
+	}
+	if !load.IsLessOrEqual(capacity) {
+		return false
+	}
+	location, time := v.StartLocation(), v.EarliestDeparture()
+	for i, act := range r.acts {
+		load = problem.AddUp(load, act.Size())
+		if !load.IsLessOrEqual(capacity) {
+			return false
+		}
+		arrTime := time + transportCosts.TransportTime(location, act.Location(), time, d, v)
+		tw := firstReachableTimeWindow(r.jobs[i].(problem.Service).TimeWindows(), arrTime)
+		if tw == nil {
+			return false
+		}
+		act.SetTheoreticalEarliestOperationStartTime(tw.Start())
+		act.SetTheoreticalLatestOperationStartTime(tw.End())
+		time = math.Max(arrTime, tw.Start()) + activityCosts.ActivityDuration(act, arrTime, d, v)
+		location = act.Location()
+	}
+	if v.IsReturnToDepot() {
+		time += transportCosts.TransportTime(location, v.EndLocation(), time, d, v)
+	}
+	return time <= v.LatestArrival() && c.fulfilsConstraints(r, v)
+}
+
+// fulfilsConstraints builds the route of v serving r activity by activity, each appended activity checked against
+// the hard route and activity constraints.
+func (c *routeChecker) fulfilsConstraints(r *savingsRoute, v problem.Vehicle) bool {
+	d := driver.NewNoDriver()
+	stateManager := c.constraintManager.StateManager()
+	vr := route.NewVehicleRouteBuilder(v, d).SetDepartureTime(v.EarliestDeparture()).Build()
+	stateManager.UpdateRoute(vr)
+	for i, act := range r.acts {
+		iContext := constraint.NewJobInsertionContext(vr, r.jobs[i], v, d, v.EarliestDeparture())
+		if !c.constraintManager.HardRouteConstraintsFulfilled(iContext) {
+			return false
+		}
+		iContext.SetActivityContext(constraint.NewActivityContext(i, 0., 0.))
+		var prevAct problem.TourActivity = vr.Start()
+		if i > 0 {
+			prevAct = r.acts[i-1]
+		}
+		if c.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, act, vr.End(), prevAct.EndTime()) != constraint.Fulfilled {
+			return false
+		}
+		vr.TourActivities().AddActivityToEnd(act)
+		if !v.IsReturnToDepot() {
+			vr.End().SetLocation(act.Location())
+		}
+		stateManager.UpdateRoute(vr)
+	}
+	return true
+}
+
+func firstReachableTimeWindow(timeWindows []problem.TimeWindow, arrTime float64) problem.TimeWindow {
+	for _, tw := range timeWindows {
+		if arrTime <= tw.End() {
+			return tw
+		}
+	}
+	return nil
+}
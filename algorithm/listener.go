@@ -40,6 +40,8 @@ type StrategySelectedListener interface {
 	InformSelectedStrategy(discoveredSolution *DiscoveredSolution, vehicleRoutingProblem *vrp.VehicleRoutingProblem, vehicleRoutingProblemSolutions []*(solution.VehicleRoutingProblemSolution))
 }
 
-type VehicleRoutingAlgorithm interface {
+// VehicleRoutingAlgorithm is an alias of an unnamed interface type such that packages imported by this package,
+// e.g. acceptors, can implement AlgorithmStartsListener without importing it.
+type VehicleRoutingAlgorithm = interface {
 	SearchSolutions() ([]*solution.VehicleRoutingProblemSolution, error)
 }
@@ -0,0 +1,208 @@
+package localsearch
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"sort"
+)
+
+var _ algorithm.SearchStrategyModule = (*InterRouteLocalSearch)(nil)
+
+// InterRouteLocalSearch improves a solution by exchanging activities between pairs of routes with exchange,
+// CROSS-exchange and 2-opt* moves until no operator finds an improving move anymore. Moves are screened in constant
+// time with the states cached by the StateManager; the promising ones are evaluated exactly and applied only if
+// both new sequences are feasible and every moved job fulfils the hard route constraints of the ConstraintManager
+// on its new route and the hard activity constraints at its new position. Routes left empty are removed.
+//
+// Added as the last module of a search strategy, it improves the solution of every ruin and recreate step.
+type InterRouteLocalSearch struct {
+	evaluator *routeEvaluator
+	estimator *segmentExchangeEvaluator
+	operators []InterRouteOperator
+	mode      ImprovementMode
+}
+
+func NewInterRouteLocalSearch(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager,
+	constraintManager *constraint.ConstraintManager) *InterRouteLocalSearch {
+	return &InterRouteLocalSearch{
+		evaluator: newRouteEvaluator(vrp, stateManager, constraintManager),
+		estimator: newSegmentExchangeEvaluator(vrp, stateManager),
+		operators: []InterRouteOperator{NewTwoOptStar(), NewExchange(), NewCrossExchange(2)},
+		mode:      FirstImprovement,
+	}
+}
+
+func (ls *InterRouteLocalSearch) SetImprovementMode(mode ImprovementMode) {
+	ls.mode = mode
+}
+
+// SetOperators replaces the operators, which are applied in the given order.
+func (ls *InterRouteLocalSearch) SetOperators(operators ...InterRouteOperator) {
+	ls.operators = operators
+}
+
+func (ls *InterRouteLocalSearch) RunAndGetSolution(sol *solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	if ls.ImproveRoutes(sol.Routes()) {
+		routes := make([]*route.VehicleRoute, 0, len(sol.Routes()))
+		for _, vr := range sol.Routes() {
+			if !vr.IsEmpty() {
+				routes = append(routes, vr)
+			}
+		}
+		sol.SetRoutes(routes)
+	}
+	return sol
+}
+
+// ImproveRoutes applies improving moves to routes until there are none left and reports whether a route has
+// changed. Routes may be left empty.
+func (ls *InterRouteLocalSearch) ImproveRoutes(routes []*route.VehicleRoute) bool {
+	for _, vr := range routes {
+		if isMovable(vr) {
+			ls.evaluator.updateRoute(vr)
+		}
+	}
+	improved := false
+	for ls.applyMove(routes) {
+		improved = true
+	}
+	return improved
+}
+
+type exchangeCandidate struct {
+	delta              float64
+	r1, r2             *route.VehicleRoute
+	acts1, acts2       []problem.TourActivity
+	movedTo1, movedTo2 []problem.TourActivity
+}
+
+// applyMove searches an improving move between two routes and applies it.
+func (ls *InterRouteLocalSearch) applyMove(routes []*route.VehicleRoute) bool {
+	candidates := make([]exchangeCandidate, 0)
+	for a, r1 := range routes {
+		for _, r2 := range routes[a+1:] {
+			if isMovable(r1) && isMovable(r2) && ls.searchPair(r1, r2, &candidates) {
+				return true
+			}
+		}
+	}
+	// the best move fulfilling the constraints
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].delta < candidates[j].delta
+	})
+	for _, c := range candidates {
+		if ls.apply(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchPair searches the improving moves between r1 and r2. It applies the first one found in the first
+// improvement mode and collects them in candidates otherwise.
+func (ls *InterRouteLocalSearch) searchPair(r1, r2 *route.VehicleRoute, candidates *[]exchangeCandidate) bool {
+	acts1 := append([]problem.TourActivity{}, r1.Activities()...)
+	acts2 := append([]problem.TourActivity{}, r2.Activities()...)
+	costs1, feasible1 := ls.evaluator.evaluate(r1, acts1)
+	costs2, feasible2 := ls.evaluator.evaluate(r2, acts2)
+	if !feasible1 || !feasible2 {
+		// routes violating a time window or a capacity already are left alone
+		return false
+	}
+	currentCosts := costs1 + costs2 + fixedCosts(r1) + fixedCosts(r2)
+	applied := false
+	for _, op := range ls.operators {
+		op.Exchanges(len(acts1), len(acts2), func(i, k, j, l int) bool {
+			delta, feasible := ls.estimator.estimate(r1, acts1, i, k, r2, acts2, j, l)
+			if k == len(acts1) && l == 0 {
+				delta -= fixedCosts(r1)
+			}
+			if l == len(acts2) && k == 0 {
+				delta -= fixedCosts(r2)
+			}
+			if !feasible || delta > -improvementThreshold {
+				return true
+			}
+			c := exchangeCandidate{
+				r1:       r1,
+				r2:       r2,
+				acts1:    splice(acts1, i, k, acts2[j:j+l]),
+				acts2:    splice(acts2, j, l, acts1[i:i+k]),
+				movedTo1: acts2[j : j+l],
+				movedTo2: acts1[i : i+k],
+			}
+			newCosts, feasible := ls.costs(c)
+			if !feasible || newCosts > currentCosts-improvementThreshold {
+				return true
+			}
+			c.delta = newCosts - currentCosts
+			if ls.mode == BestImprovement {
+				*candidates = append(*candidates, c)
+				return true
+			}
+			applied = ls.apply(c)
+			return !applied
+		})
+		if applied {
+			return true
+		}
+	}
+	return false
+}
+
+// costs returns the exact costs of both routes of c and whether both are feasible.
+func (ls *InterRouteLocalSearch) costs(c exchangeCandidate) (float64, bool) {
+	costs1, feasible1 := ls.evaluator.evaluate(c.r1, c.acts1)
+	costs2, feasible2 := ls.evaluator.evaluate(c.r2, c.acts2)
+	costs := costs1 + costs2
+	if len(c.acts1) > 0 {
+		costs += fixedCosts(c.r1)
+	}
+	if len(c.acts2) > 0 {
+		costs += fixedCosts(c.r2)
+	}
+	return costs, feasible1 && feasible2
+}
+
+// apply applies c if the moved jobs fulfil the hard route and activity constraints, restoring both routes otherwise.
+func (ls *InterRouteLocalSearch) apply(c exchangeCandidate) bool {
+	original1 := append([]problem.TourActivity{}, c.r1.Activities()...)
+	if !ls.evaluator.fulfilsConstraints(c.r1, c.acts1, c.movedTo1) {
+		return false
+	}
+	if !ls.evaluator.fulfilsConstraints(c.r2, c.acts2, c.movedTo2) {
+		ls.evaluator.setActivities(c.r1, original1)
+		return false
+	}
+	return true
+}
+
+func (ls *InterRouteLocalSearch) Name() string {
+	return "interRouteLocalSearch"
+}
+
+// AddModuleListener does nothing, the local search does not inform listeners.
+func (ls *InterRouteLocalSearch) AddModuleListener(moduleListener algorithm.SearchStrategyModuleListener) {
+}
+
+func (ls *InterRouteLocalSearch) String() string {
+	return fmt.Sprintf("[name=interRouteLocalSearch][mode=%v][#operators=%d]", ls.mode, len(ls.operators))
+}
+
+// splice returns acts with the k activities starting at i replaced by segment.
+func splice(acts []problem.TourActivity, i, k int, segment []problem.TourActivity) []problem.TourActivity {
+	res := make([]problem.TourActivity, 0, len(acts)-k+len(segment))
+	res = append(res, acts[:i]...)
+	res = append(res, segment...)
+	return append(res, acts[i+k:]...)
+}
+
+func fixedCosts(vr *route.VehicleRoute) float64 {
+	return vr.Vehicle().Type().VehicleCostParams().Fix()
+}
@@ -0,0 +1,220 @@
+package localsearch
+
+import (
+	"testing"
+
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newInterRouteLocalSearch(p *vrp.VehicleRoutingProblem) (*InterRouteLocalSearch, *constraint.ConstraintManager) {
+	sm := state.NewStateManager(p)
+	cm := constraint.NewConstraintManager(p, sm)
+	return NewInterRouteLocalSearch(p, sm, cm), cm
+}
+
+func newOpenVehicles(capacity int, fixedCosts float64) (*vehicle.Vehicle, *vehicle.Vehicle) {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, capacity).SetFixedCost(fixedCosts).Build()
+	newOpenVehicle := func(id string) *vehicle.Vehicle {
+		return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+			SetReturnToDepot(false).Build()
+	}
+	return newOpenVehicle("v1"), newOpenVehicle("v2")
+}
+
+func TestInterRouteLocalSearch_OperatorsShouldRemoveCrossing(t *testing.T) {
+	// a capacity of two keeps the routes from being merged
+	v1, v2 := newOpenVehicles(2, 0.)
+	a1, a2 := newService("a1", 10, 10), newService("a2", 20, -10)
+	b1, b2 := newService("b1", 10, -10), newService("b2", 20, 10)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddJob(a1).AddJob(a2).AddJob(b1).AddJob(b2).Build()
+	for _, op := range []InterRouteOperator{NewTwoOptStar(), NewExchange()} {
+		for _, mode := range []ImprovementMode{FirstImprovement, BestImprovement} {
+			r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddService(a1).AddService(a2).Build()
+			r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).AddService(b1).AddService(b2).Build()
+			ls, _ := newInterRouteLocalSearch(p)
+			ls.SetOperators(op)
+			ls.SetImprovementMode(mode)
+
+			assert.True(t, ls.ImproveRoutes([]*route.VehicleRoute{r1, r2}), op.Name())
+
+			assert.ElementsMatch(t, [][]string{{"a1", "b2"}, {"b1", "a2"}}, [][]string{jobIds(r1), jobIds(r2)}, op.Name())
+			assert.InDelta(t, 48.2843, routeCosts(p, r1)+routeCosts(p, r2), 1e-4, op.Name())
+			assert.False(t, ls.ImproveRoutes([]*route.VehicleRoute{r1, r2}), op.Name())
+		}
+	}
+}
+
+func TestInterRouteLocalSearch_CrossExchangeShouldExchangeSegments(t *testing.T) {
+	v1, v2 := newOpenVehicles(3, 0.)
+	a1, a2, a3 := newService("a1", 10, 10), newService("a2", 20, -10), newService("a3", 30, -10)
+	b1, b2 := newService("b1", 10, -10), newService("b2", 20, 10)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddJob(a1).AddJob(a2).AddJob(a3).AddJob(b1).AddJob(b2).Build()
+	r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddService(a1).AddService(a2).AddService(a3).Build()
+	r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).AddService(b1).AddService(b2).Build()
+	ls, _ := newInterRouteLocalSearch(p)
+	ls.SetOperators(NewCrossExchange(2))
+	ls.SetImprovementMode(BestImprovement)
+
+	assert.True(t, ls.ImproveRoutes([]*route.VehicleRoute{r1, r2}))
+
+	assert.Equal(t, []string{"a1", "b2"}, jobIds(r1))
+	assert.Equal(t, []string{"b1", "a2", "a3"}, jobIds(r2))
+	assert.InDelta(t, 58.2843, routeCosts(p, r1)+routeCosts(p, r2), 1e-4)
+}
+
+func newLineRoutes(v1, v2 *vehicle.Vehicle, delivery bool) (*vrp.VehicleRoutingProblem, *route.VehicleRoute, *route.VehicleRoute) {
+	jobs := make([]problem.Job, 0)
+	builders := []*route.VehicleRouteBuilder{route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()),
+		route.NewVehicleRouteBuilder(v2, driver.NewNoDriver())}
+	for i, x := range []float64{10, 20, 30, 40} {
+		b := builders[i/2]
+		id := string(rune('a' + i))
+		if delivery {
+			d := job.NewDeliveryBuilder(id).SetLocation(problem.NewLocationWithCoordinate(x, 0)).AddSizeDimension(0, 1).Build()
+			jobs = append(jobs, d)
+			b.AddDelivery(d)
+		} else {
+			s := newService(id, x, 0)
+			jobs = append(jobs, s)
+			b.AddService(s)
+		}
+	}
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddAllJobs(jobs).Build()
+	return p, builders[0].Build(), builders[1].Build()
+}
+
+func TestInterRouteLocalSearch_ShouldMergeRoutesToSaveFixedCosts(t *testing.T) {
+	for _, delivery := range []bool{false, true} {
+		v1, v2 := newOpenVehicles(4, 100.)
+		p, r1, r2 := newLineRoutes(v1, v2, delivery)
+		ls, _ := newInterRouteLocalSearch(p)
+		ls.SetImprovementMode(BestImprovement)
+
+		sol := ls.RunAndGetSolution(solution.NewVehicleRoutingProblemSolution([]*route.VehicleRoute{r1, r2}, 0.))
+
+		assert.Len(t, sol.Routes(), 1)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, jobIds(sol.Routes()[0]))
+	}
+}
+
+func TestInterRouteLocalSearch_ShouldRespectCapacities(t *testing.T) {
+	for _, delivery := range []bool{false, true} {
+		v1, v2 := newOpenVehicles(2, 100.)
+		p, r1, r2 := newLineRoutes(v1, v2, delivery)
+		ls, _ := newInterRouteLocalSearch(p)
+
+		sol := ls.RunAndGetSolution(solution.NewVehicleRoutingProblemSolution([]*route.VehicleRoute{r1, r2}, 0.))
+
+		assert.Len(t, sol.Routes(), 2)
+		assert.Equal(t, []string{"a", "b"}, jobIds(r1))
+		assert.Equal(t, []string{"c", "d"}, jobIds(r2))
+	}
+}
+
+func TestInterRouteLocalSearch_ShouldKeepShipmentsTogether(t *testing.T) {
+	v1, v2 := newOpenVehicles(1, 0.)
+	s1 := job.NewShipmentBuilder("s1").SetPickupLocation(problem.NewLocationWithCoordinate(10, 10)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(20, -10)).AddSizeDimension(0, 1).Build()
+	s2 := job.NewShipmentBuilder("s2").SetPickupLocation(problem.NewLocationWithCoordinate(10, -10)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(20, 10)).AddSizeDimension(0, 1).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddJob(s1).AddJob(s2).Build()
+	r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddPickupForShipment(s1).AddDeliveryForShipment(s1).Build()
+	r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).AddPickupForShipment(s2).AddDeliveryForShipment(s2).Build()
+	ls, _ := newInterRouteLocalSearch(p)
+
+	ls.ImproveRoutes([]*route.VehicleRoute{r1, r2})
+
+	for _, vr := range []*route.VehicleRoute{r1, r2} {
+		acts := vr.Activities()
+		for i := 0; i < len(acts); i += 2 {
+			assert.Equal(t, "pickupShipment", acts[i].Name())
+			assert.Equal(t, "deliverShipment", acts[i+1].Name())
+			assert.Equal(t, acts[i].(problem.JobActivity).Job(), acts[i+1].(problem.JobActivity).Job())
+		}
+	}
+}
+
+func TestInterRouteLocalSearch_ShouldRespectHardActivityConstraints(t *testing.T) {
+	v1, v2 := newOpenVehicles(2, 0.)
+	a1, a2 := newService("a1", 10, 10), newService("a2", 20, -10)
+	b1, b2 := newService("b1", 10, -10), newService("b2", 20, 10)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddJob(a1).AddJob(a2).AddJob(b1).AddJob(b2).Build()
+	r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddService(a1).AddService(a2).Build()
+	r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).AddService(b1).AddService(b2).Build()
+	ls, cm := newInterRouteLocalSearch(p)
+	cm.AddHardActivityConstraint(&forbiddenSuccessor{prev: "b1", next: "a2"})
+	ls.SetOperators(NewTwoOptStar())
+
+	ls.ImproveRoutes([]*route.VehicleRoute{r1, r2})
+
+	assert.Equal(t, []string{"a1", "a2"}, jobIds(r1))
+	assert.Equal(t, []string{"b1", "b2"}, jobIds(r2))
+}
+
+// forbiddenVehicle is a hard route constraint keeping a job from the routes of a vehicle.
+type forbiddenVehicle struct {
+	job, vehicle string
+}
+
+func (c *forbiddenVehicle) Fulfilled(iContext *constraint.JobInsertionContext) bool {
+	return iContext.Job().Id() != c.job || iContext.NewVehicle().Id() != c.vehicle
+}
+
+func TestInterRouteLocalSearch_ShouldRespectHardRouteConstraints(t *testing.T) {
+	v1, v2 := newOpenVehicles(2, 0.)
+	a1, a2 := newService("a1", 10, 10), newService("a2", 20, -10)
+	b1, b2 := newService("b1", 10, -10), newService("b2", 20, 10)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).AddJob(a1).AddJob(a2).AddJob(b1).AddJob(b2).Build()
+	r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddService(a1).AddService(a2).Build()
+	r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).AddService(b1).AddService(b2).Build()
+	ls, cm := newInterRouteLocalSearch(p)
+	cm.AddHardRouteConstraint(&forbiddenVehicle{job: "b2", vehicle: "v1"})
+
+	ls.ImproveRoutes([]*route.VehicleRoute{r1, r2})
+
+	assert.NotContains(t, jobIds(r1), "b2")
+	assert.Contains(t, jobIds(r2), "b2")
+}
+
+func TestSegmentExchangeEvaluator_ShouldAcceptEveryFeasibleExchange(t *testing.T) {
+	v1, v2 := newOpenVehicles(3, 0.)
+	newPickup := func(id string, x, y float64) *job.Pickup {
+		return job.NewPickupBuilder(id).SetLocation(problem.NewLocationWithCoordinate(x, y)).AddSizeDimension(0, 1).Build()
+	}
+	newDelivery := func(id string, x, y float64) *job.Delivery {
+		return job.NewDeliveryBuilder(id).SetLocation(problem.NewLocationWithCoordinate(x, y)).AddSizeDimension(0, 1).Build()
+	}
+	p1, d1, s1 := newPickup("p1", 10, 0), newDelivery("d1", 20, 5), newService("s1", 30, 0)
+	d2, p2, d3, p3 := newDelivery("d2", 0, 10), newPickup("p2", 5, 20), newDelivery("d3", 10, 30), newPickup("p3", 0, 40)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2).
+		AddAllJobs([]problem.Job{p1, d1, s1, d2, p2, d3, p3}).Build()
+	r1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).AddPickup(p1).AddDelivery(d1).AddService(s1).Build()
+	r2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver()).
+		AddDelivery(d2).AddPickup(p2).AddDelivery(d3).AddPickup(p3).Build()
+	sm := state.NewStateManager(p)
+	ls := NewInterRouteLocalSearch(p, sm, constraint.NewConstraintManager(p, sm))
+	ls.evaluator.updateRoute(r1)
+	ls.evaluator.updateRoute(r2)
+	acts1, acts2 := r1.Activities(), r2.Activities()
+
+	for _, op := range []InterRouteOperator{NewTwoOptStar(), NewExchange(), NewCrossExchange(4)} {
+		op.Exchanges(len(acts1), len(acts2), func(i, k, j, l int) bool {
+			_, feasible1 := ls.evaluator.evaluate(r1, splice(acts1, i, k, acts2[j:j+l]))
+			_, feasible2 := ls.evaluator.evaluate(r2, splice(acts2, j, l, acts1[i:i+k]))
+			_, estimated := ls.estimator.estimate(r1, acts1, i, k, r2, acts2, j, l)
+			assert.Equal(t, feasible1 && feasible2, estimated, "%s %d %d %d %d", op.Name(), i, k, j, l)
+			return true
+		})
+	}
+}
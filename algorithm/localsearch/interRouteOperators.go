@@ -0,0 +1,106 @@
+package localsearch
+
+import "fmt"
+
+// InterRouteOperator defines a neighbourhood of two routes as exchanges of segments: the activities [i,i+k) of
+// the first route are exchanged with the activities [j,j+l) of the second one.
+type InterRouteOperator interface {
+	// Exchanges calls yield with every exchange of two routes serving n1 and n2 activities, until yield returns
+	// false.
+	Exchanges(n1, n2 int, yield func(i, k, j, l int) bool)
+	Name() string
+}
+
+var (
+	_ InterRouteOperator = (*Exchange)(nil)
+	_ InterRouteOperator = (*CrossExchange)(nil)
+	_ InterRouteOperator = (*TwoOptStar)(nil)
+)
+
+// Exchange swaps two activities of different routes, i.e. two services.
+type Exchange struct{}
+
+func NewExchange() *Exchange {
+	return &Exchange{}
+}
+
+func (o *Exchange) Exchanges(n1, n2 int, yield func(i, k, j, l int) bool) {
+	for i := 0; i < n1; i++ {
+		for j := 0; j < n2; j++ {
+			if !yield(i, 1, j, 1) {
+				return
+			}
+		}
+	}
+}
+
+func (o *Exchange) Name() string {
+	return "exchange"
+}
+
+// CrossExchange swaps two segments of one up to maxSegmentLength consecutive activities of different routes,
+// keeping the order within the segments (Taillard et al. 1997).
+type CrossExchange struct {
+	maxSegmentLength int
+}
+
+func NewCrossExchange(maxSegmentLength int) *CrossExchange {
+	if maxSegmentLength < 1 {
+		panic("max segment length must be at least 1")
+	}
+	return &CrossExchange{
+		maxSegmentLength: maxSegmentLength,
+	}
+}
+
+func (o *CrossExchange) Exchanges(n1, n2 int, yield func(i, k, j, l int) bool) {
+	for k := 1; k <= o.maxSegmentLength; k++ {
+		for l := 1; l <= o.maxSegmentLength; l++ {
+			if k == 1 && l == 1 {
+				// left to Exchange
+				continue
+			}
+			for i := 0; i+k <= n1; i++ {
+				for j := 0; j+l <= n2; j++ {
+					if !yield(i, k, j, l) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func (o *CrossExchange) Name() string {
+	return "crossExchange"
+}
+
+func (o *CrossExchange) String() string {
+	return fmt.Sprintf("[name=crossExchange][maxSegmentLength=%d]", o.maxSegmentLength)
+}
+
+// TwoOptStar exchanges the tails of two routes (Potvin and Rousseau 1995), which removes crossings of different
+// routes.
+type TwoOptStar struct{}
+
+func NewTwoOptStar() *TwoOptStar {
+	return &TwoOptStar{}
+}
+
+func (o *TwoOptStar) Exchanges(n1, n2 int, yield func(i, k, j, l int) bool) {
+	for i := 0; i <= n1; i++ {
+		for j := 0; j <= n2; j++ {
+			// exchanging nothing or everything does not change anything
+			if (i == n1 && j == n2) || (i == 0 && j == 0) {
+				continue
+			}
+			if !yield(i, n1-i, j, n2-j) {
+				return
+			}
+		}
+	}
+}
+
+func (o *TwoOptStar) Name() string {
+	return "twoOptStar"
+}
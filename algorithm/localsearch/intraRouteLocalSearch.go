@@ -0,0 +1,142 @@
+package localsearch
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"sort"
+)
+
+// ImprovementMode defines which of the improving moves a local search applies.
+type ImprovementMode int
+
+const (
+	// FirstImprovement applies the first improving move found.
+	FirstImprovement ImprovementMode = iota
+	// BestImprovement applies the best move of the whole neighbourhood.
+	BestImprovement
+)
+
+func (m ImprovementMode) String() string {
+	switch m {
+	case FirstImprovement:
+		return "firstImprovement"
+	case BestImprovement:
+		return "bestImprovement"
+	}
+	return "unknown"
+}
+
+var _ algorithm.SearchStrategyModule = (*IntraRouteLocalSearch)(nil)
+
+// IntraRouteLocalSearch improves every route of a solution on its own by reordering its activities with 2-opt,
+// Or-opt and relocate moves until no operator finds an improving move anymore. A move is applied only if the new
+// sequence is feasible with respect to capacities, time windows and shipment precedence and if every activity
+// moved fulfils the hard activity constraints of the ConstraintManager at its new position. The states of the
+// routes are updated with the StateManager.
+//
+// Added as the last module of a search strategy, it improves the solution of every ruin and recreate step.
+type IntraRouteLocalSearch struct {
+	evaluator *routeEvaluator
+	operators []IntraRouteOperator
+	mode      ImprovementMode
+}
+
+func NewIntraRouteLocalSearch(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager,
+	constraintManager *constraint.ConstraintManager) *IntraRouteLocalSearch {
+	return &IntraRouteLocalSearch{
+		evaluator: newRouteEvaluator(vrp, stateManager, constraintManager),
+		operators: []IntraRouteOperator{NewTwoOpt(), NewOrOpt(3), NewRelocate()},
+		mode:      FirstImprovement,
+	}
+}
+
+func (ls *IntraRouteLocalSearch) SetImprovementMode(mode ImprovementMode) {
+	ls.mode = mode
+}
+
+// SetOperators replaces the operators, which are applied in the given order.
+func (ls *IntraRouteLocalSearch) SetOperators(operators ...IntraRouteOperator) {
+	ls.operators = operators
+}
+
+func (ls *IntraRouteLocalSearch) RunAndGetSolution(sol *solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	for _, vr := range sol.Routes() {
+		ls.ImproveRoute(vr)
+	}
+	return sol
+}
+
+// ImproveRoute applies improving moves to vr until there are none left and reports whether vr has changed.
+func (ls *IntraRouteLocalSearch) ImproveRoute(vr *route.VehicleRoute) bool {
+	if !isMovable(vr) {
+		return false
+	}
+	ls.evaluator.updateRoute(vr)
+	improved := false
+	for ls.applyMove(vr) {
+		improved = true
+	}
+	return improved
+}
+
+type candidate struct {
+	costs            float64
+	neighbour, moved []problem.TourActivity
+}
+
+// applyMove searches an improving move and applies it.
+func (ls *IntraRouteLocalSearch) applyMove(vr *route.VehicleRoute) bool {
+	acts := append([]problem.TourActivity{}, vr.Activities()...)
+	currentCosts, feasible := ls.evaluator.evaluate(vr, acts)
+	if !feasible {
+		// routes violating a time window or a capacity already are left alone
+		return false
+	}
+	candidates := make([]candidate, 0)
+	applied := false
+	for _, op := range ls.operators {
+		op.Neighbours(acts, func(neighbour, moved []problem.TourActivity) bool {
+			costs, feasible := ls.evaluator.evaluate(vr, neighbour)
+			if !feasible || costs > currentCosts-improvementThreshold {
+				return true
+			}
+			if ls.mode == BestImprovement {
+				candidates = append(candidates, candidate{costs: costs, neighbour: neighbour, moved: moved})
+				return true
+			}
+			applied = ls.evaluator.fulfilsConstraints(vr, neighbour, moved)
+			return !applied
+		})
+		if applied {
+			return true
+		}
+	}
+	// the best move fulfilling the constraints
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].costs < candidates[j].costs
+	})
+	for _, c := range candidates {
+		if ls.evaluator.fulfilsConstraints(vr, c.neighbour, c.moved) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ls *IntraRouteLocalSearch) Name() string {
+	return "intraRouteLocalSearch"
+}
+
+// AddModuleListener does nothing, the local search does not inform listeners.
+func (ls *IntraRouteLocalSearch) AddModuleListener(moduleListener algorithm.SearchStrategyModuleListener) {
+}
+
+func (ls *IntraRouteLocalSearch) String() string {
+	return fmt.Sprintf("[name=intraRouteLocalSearch][mode=%v][#operators=%d]", ls.mode, len(ls.operators))
+}
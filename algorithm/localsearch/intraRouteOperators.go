@@ -0,0 +1,180 @@
+package localsearch
+
+import (
+	"fmt"
+	"gsprit/problem"
+)
+
+// IntraRouteOperator defines a neighbourhood of the activity sequence of a single route.
+type IntraRouteOperator interface {
+	// Neighbours calls yield with every neighbour of acts and the activities that have been moved, until yield
+	// returns false. Both slices are new ones and may be kept by yield.
+	Neighbours(acts []problem.TourActivity, yield func(neighbour, moved []problem.TourActivity) bool)
+	Name() string
+}
+
+var (
+	_ IntraRouteOperator = (*TwoOpt)(nil)
+	_ IntraRouteOperator = (*OrOpt)(nil)
+	_ IntraRouteOperator = (*Relocate)(nil)
+)
+
+// TwoOpt reverses a segment of the route, which removes two edges and reconnects the route the other way round.
+type TwoOpt struct{}
+
+func NewTwoOpt() *TwoOpt {
+	return &TwoOpt{}
+}
+
+func (o *TwoOpt) Neighbours(acts []problem.TourActivity, yield func(neighbour, moved []problem.TourActivity) bool) {
+	for i := 0; i < len(acts)-1; i++ {
+		for j := i + 1; j < len(acts); j++ {
+			neighbour := append([]problem.TourActivity{}, acts...)
+			for l, r := i, j; l < r; l, r = l+1, r-1 {
+				neighbour[l], neighbour[r] = neighbour[r], neighbour[l]
+			}
+			if !yield(neighbour, append([]problem.TourActivity{}, neighbour[i:j+1]...)) {
+				return
+			}
+		}
+	}
+}
+
+func (o *TwoOpt) Name() string {
+	return "twoOpt"
+}
+
+// OrOpt moves a segment of one up to maxSegmentLength consecutive activities to another position of the route,
+// keeping the order of the segment.
+type OrOpt struct {
+	maxSegmentLength int
+}
+
+func NewOrOpt(maxSegmentLength int) *OrOpt {
+	if maxSegmentLength < 1 {
+		panic("max segment length must be at least 1")
+	}
+	return &OrOpt{
+		maxSegmentLength: maxSegmentLength,
+	}
+}
+
+func (o *OrOpt) Neighbours(acts []problem.TourActivity, yield func(neighbour, moved []problem.TourActivity) bool) {
+	for length := 1; length <= o.maxSegmentLength && length < len(acts); length++ {
+		for i := 0; i+length <= len(acts); i++ {
+			segment := acts[i : i+length]
+			rest := append(append([]problem.TourActivity{}, acts[:i]...), acts[i+length:]...)
+			for position := 0; position <= len(rest); position++ {
+				if position == i {
+					continue
+				}
+				if !yield(insertAt(rest, position, segment), append([]problem.TourActivity{}, segment...)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (o *OrOpt) Name() string {
+	return "orOpt"
+}
+
+func (o *OrOpt) String() string {
+	return fmt.Sprintf("[name=orOpt][maxSegmentLength=%d]", o.maxSegmentLength)
+}
+
+// Relocate moves a job to other positions of the route. Both activities of a shipment are moved, the pickup
+// staying in front of the delivery.
+type Relocate struct{}
+
+func NewRelocate() *Relocate {
+	return &Relocate{}
+}
+
+func (o *Relocate) Neighbours(acts []problem.TourActivity, yield func(neighbour, moved []problem.TourActivity) bool) {
+	for _, jobActs := range activitiesByJob(acts) {
+		rest := without(acts, jobActs)
+		if len(jobActs) == 1 {
+			for position := 0; position <= len(rest); position++ {
+				neighbour := insertAt(rest, position, jobActs)
+				if !equal(neighbour, acts) && !yield(neighbour, append([]problem.TourActivity{}, jobActs...)) {
+					return
+				}
+			}
+			continue
+		}
+		for first := 0; first <= len(rest); first++ {
+			for second := first; second <= len(rest); second++ {
+				neighbour := insertAt(insertAt(rest, second, jobActs[1:]), first, jobActs[:1])
+				if !equal(neighbour, acts) && !yield(neighbour, append([]problem.TourActivity{}, jobActs...)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (o *Relocate) Name() string {
+	return "relocate"
+}
+
+// activitiesByJob groups acts by their job, in the order the jobs appear first.
+func activitiesByJob(acts []problem.TourActivity) [][]problem.TourActivity {
+	res := make([][]problem.TourActivity, 0)
+	index := make(map[problem.Job]int)
+	for _, act := range acts {
+		jobAct, ok := act.(problem.JobActivity)
+		if !ok {
+			continue
+		}
+		i, exists := index[jobAct.Job()]
+		if !exists {
+			i = len(res)
+			index[jobAct.Job()] = i
+			res = append(res, nil)
+		}
+		res[i] = append(res[i], act)
+	}
+	return res
+}
+
+// insertAt returns a new slice with segment inserted into acts at position.
+func insertAt(acts []problem.TourActivity, position int, segment []problem.TourActivity) []problem.TourActivity {
+	res := make([]problem.TourActivity, 0, len(acts)+len(segment))
+	res = append(res, acts[:position]...)
+	res = append(res, segment...)
+	return append(res, acts[position:]...)
+}
+
+// without returns a new slice with the activities of acts not contained in removed.
+func without(acts, removed []problem.TourActivity) []problem.TourActivity {
+	res := make([]problem.TourActivity, 0, len(acts))
+	for _, act := range acts {
+		if !contains(removed, act) {
+			res = append(res, act)
+		}
+	}
+	return res
+}
+
+func contains(acts []problem.TourActivity, act problem.TourActivity) bool {
+	for _, a := range acts {
+		if a == act {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b []problem.TourActivity) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package localsearch
+
+import (
+	"testing"
+
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVehicle(id string, returnToDepot bool) *vehicle.Vehicle {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 10).Build()
+	return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		SetReturnToDepot(returnToDepot).Build()
+}
+
+func newService(id string, x, y float64) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).SetLocation(problem.NewLocationWithCoordinate(x, y)).
+		AddSizeDimension(0, 1).Build()
+}
+
+func newLocalSearch(p *vrp.VehicleRoutingProblem) (*IntraRouteLocalSearch, *constraint.ConstraintManager) {
+	sm := state.NewStateManager(p)
+	cm := constraint.NewConstraintManager(p, sm)
+	return NewIntraRouteLocalSearch(p, sm, cm), cm
+}
+
+func jobIds(vr *route.VehicleRoute) []string {
+	ids := make([]string, 0)
+	for _, act := range vr.Activities() {
+		ids = append(ids, act.(problem.JobActivity).Job().Id())
+	}
+	return ids
+}
+
+func routeCosts(p *vrp.VehicleRoutingProblem, vr *route.VehicleRoute) float64 {
+	costs, _ := newRouteEvaluator(p, state.NewStateManager(p), nil).evaluate(vr, vr.Activities())
+	return costs
+}
+
+func newCrossingRoute() (*vrp.VehicleRoutingProblem, *route.VehicleRoute) {
+	v := newVehicle("v", true)
+	s1, s2, s3 := newService("s1", 10, 0), newService("s2", 10, 10), newService("s3", 0, 10)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(s1).AddJob(s2).AddJob(s3).Build()
+	vr := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(s1).AddService(s3).AddService(s2).Build()
+	return p, vr
+}
+
+func TestIntraRouteLocalSearch_ShouldRemoveCrossing(t *testing.T) {
+	for _, mode := range []ImprovementMode{FirstImprovement, BestImprovement} {
+		p, vr := newCrossingRoute()
+		ls, _ := newLocalSearch(p)
+		ls.SetImprovementMode(mode)
+
+		sol := ls.RunAndGetSolution(solution.NewVehicleRoutingProblemSolution([]*route.VehicleRoute{vr}, 0.))
+
+		assert.Same(t, vr, sol.Routes()[0])
+		assert.Equal(t, []string{"s1", "s2", "s3"}, jobIds(vr))
+		assert.InDelta(t, 40., routeCosts(p, vr), 1e-9)
+		assert.InDelta(t, 40., vr.End().ArrTime(), 1e-9)
+	}
+}
+
+func TestIntraRouteLocalSearch_OperatorsShouldMoveSegments(t *testing.T) {
+	v := newVehicle("v", true)
+	s := []*job.Service{newService("s1", 10, 0), newService("s2", 20, 0), newService("s3", 30, 0), newService("s4", 40, 0)}
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(s[0]).AddJob(s[1]).AddJob(s[2]).AddJob(s[3]).Build()
+	for _, op := range []IntraRouteOperator{NewOrOpt(2), NewRelocate(), NewTwoOpt()} {
+		vr := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).
+			AddService(s[2]).AddService(s[3]).AddService(s[0]).AddService(s[1]).Build()
+		ls, _ := newLocalSearch(p)
+		ls.SetOperators(op)
+
+		assert.True(t, ls.ImproveRoute(vr), op.Name())
+
+		assert.InDelta(t, 80., routeCosts(p, vr), 1e-9, op.Name())
+		assert.False(t, ls.ImproveRoute(vr), op.Name())
+	}
+}
+
+func TestIntraRouteLocalSearch_ShouldKeepPickupBeforeDelivery(t *testing.T) {
+	v := newVehicle("v", false)
+	shipment := job.NewShipmentBuilder("shipment").
+		SetPickupLocation(problem.NewLocationWithCoordinate(20, 0)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(10, 0)).
+		AddSizeDimension(0, 1).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(shipment).Build()
+	vr := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).
+		AddPickupForShipment(shipment).AddDeliveryForShipment(shipment).Build()
+	ls, _ := newLocalSearch(p)
+
+	assert.False(t, ls.ImproveRoute(vr))
+	assert.Equal(t, "pickupShipment", vr.Activities()[0].Name())
+}
+
+func TestIntraRouteLocalSearch_ShouldRespectTimeWindows(t *testing.T) {
+	v := newVehicle("v", false)
+	tw, _ := activity.NewTimeWindow(0., 10.)
+	early := job.NewServiceBuilder[*job.Service]("early").SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+		AddTimeWindow(tw).Build()
+	other := newService("other", -5, 0)
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(early).AddJob(other).Build()
+	vr := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(early).AddService(other).Build()
+	ls, _ := newLocalSearch(p)
+	ls.SetImprovementMode(BestImprovement)
+
+	assert.False(t, ls.ImproveRoute(vr))
+	assert.Equal(t, []string{"early", "other"}, jobIds(vr))
+}
+
+type forbiddenSuccessor struct {
+	prev, next string
+}
+
+func (c *forbiddenSuccessor) Fulfilled(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) constraint.ConstraintsStatus {
+	id := func(act problem.TourActivity) string {
+		if jobAct, ok := act.(problem.JobActivity); ok {
+			return jobAct.Job().Id()
+		}
+		return ""
+	}
+	if (id(prevAct) == c.prev && id(newAct) == c.next) || (id(newAct) == c.prev && id(nextAct) == c.next) {
+		return constraint.NotFulfilled
+	}
+	return constraint.Fulfilled
+}
+
+func TestIntraRouteLocalSearch_ShouldRespectHardActivityConstraints(t *testing.T) {
+	p, vr := newCrossingRoute()
+	ls, cm := newLocalSearch(p)
+	cm.AddHardActivityConstraint(&forbiddenSuccessor{prev: "s1", next: "s2"})
+
+	ls.ImproveRoute(vr)
+
+	ids := jobIds(vr)
+	for i := 0; i+1 < len(ids); i++ {
+		assert.False(t, ids[i] == "s1" && ids[i+1] == "s2", ids)
+	}
+	assert.Equal(t, []string{"s3", "s2", "s1"}, ids)
+}
@@ -0,0 +1,180 @@
+package localsearch
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+// improvementThreshold is the minimum cost reduction of a move, which keeps rounding errors from cycling moves.
+const improvementThreshold = 1e-9
+
+// routeEvaluator evaluates the activity sequences local search operators propose for a route.
+type routeEvaluator struct {
+	vrp               *vrp.VehicleRoutingProblem
+	stateManager      *state.StateManager
+	constraintManager *constraint.ConstraintManager
+}
+
+func newRouteEvaluator(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager, constraintManager *constraint.ConstraintManager) *routeEvaluator {
+	return &routeEvaluator{
+		vrp:               vrp,
+		stateManager:      stateManager,
+		constraintManager: constraintManager,
+	}
+}
+
+// evaluate returns the transport and activity costs of vr serving acts in the given order and whether the
+// sequence is feasible, i.e. every activity starts within its time window, the vehicle is back in time and never
+// exceeds its capacity, and every shipment is both picked up and delivered, the pickup first.
+func (e *routeEvaluator) evaluate(vr *route.VehicleRoute, acts []problem.TourActivity) (float64, bool) {
+	transportCosts, activityCosts := e.vrp.TransportCosts(), e.vrp.ActivityCosts()
+	v, d := vr.Vehicle(), vr.Driver()
+	capacity := v.Type().CapacityDimensions()
+	load := problem.NewCapacity(nil)
+	for _, act := range acts {
+		if _, ok := act.(*activity.DeliverService); ok {
+			load = problem.AddUp(load, problem.Invert(act.Size()))
+		}
+	}
+	if !load.IsLessOrEqual(capacity) {
+		return 0., false
+	}
+	pickedUp := make(map[problem.Job]bool)
+	costs := 0.
+	prevLocation := vr.Start().Location()
+	prevEndTime, _ := vr.DepartureTime()
+	for _, act := range acts {
+		switch a := act.(type) {
+		case *activity.PickupShipment:
+			pickedUp[a.Job()] = true
+		case *activity.DeliverShipment:
+			if !pickedUp[a.Job()] {
+				return 0., false
+			}
+			delete(pickedUp, a.Job())
+		}
+		load = problem.AddUp(load, act.Size())
+		if !load.IsLessOrEqual(capacity) {
+			return 0., false
+		}
+		arrTime := prevEndTime + transportCosts.TransportTime(prevLocation, act.Location(), prevEndTime, d, v)
+		if arrTime > act.TheoreticalLatestOperationStartTime() {
+			return 0., false
+		}
+		costs += transportCosts.TransportCost(prevLocation, act.Location(), prevEndTime, d, v) +
+			activityCosts.ActivityCost(act, arrTime, d, v)
+		prevLocation = act.Location()
+		prevEndTime = math.Max(arrTime, act.TheoreticalEarliestOperationStartTime()) + activityCosts.ActivityDuration(act, arrTime, d, v)
+	}
+	// a vehicle not returning to its depot ends its route right after the last activity
+	if v.IsReturnToDepot() {
+		costs += transportCosts.TransportCost(prevLocation, vr.End().Location(), prevEndTime, d, v)
+		prevEndTime += transportCosts.TransportTime(prevLocation, vr.End().Location(), prevEndTime, d, v)
+	}
+	return costs, len(pickedUp) == 0 && prevEndTime <= v.LatestArrival()
+}
+
+// fulfilsConstraints applies acts to vr by removing the moved activities and reinserting them one after another,
+// each checked against the hard route and activity constraints of the ConstraintManager. It reports whether all
+// reinsertions are feasible; if not, the original sequence of vr is restored. The states of vr are up to date
+// either way.
+func (e *routeEvaluator) fulfilsConstraints(vr *route.VehicleRoute, acts, moved []problem.TourActivity) bool {
+	original := append([]problem.TourActivity{}, vr.Activities()...)
+	isMoved := make(map[problem.TourActivity]bool, len(moved))
+	for _, act := range moved {
+		isMoved[act] = true
+	}
+	remaining := make([]problem.TourActivity, 0, len(acts))
+	for _, act := range acts {
+		if !isMoved[act] {
+			remaining = append(remaining, act)
+		}
+	}
+	e.setActivities(vr, remaining)
+	for i, act := range acts {
+		if !isMoved[act] {
+			continue
+		}
+		// the moved activities preceding act have been inserted already, thus act goes to position i
+		if !e.isInsertable(vr, acts, i, isMoved) {
+			e.setActivities(vr, original)
+			return false
+		}
+		isMoved[act] = false
+		vr.TourActivities().AddActivity(i, act)
+		e.updateRoute(vr)
+	}
+	return true
+}
+
+// isInsertable checks the insertion of acts[i] into vr, which serves acts apart from the moved activities not yet
+// inserted. The hard route constraints are checked with the first activity of a job, i.e. the pickup of a shipment.
+func (e *routeEvaluator) isInsertable(vr *route.VehicleRoute, acts []problem.TourActivity, i int, isMoved map[problem.TourActivity]bool) bool {
+	act := acts[i]
+	jobAct, ok := act.(problem.JobActivity)
+	if !ok {
+		return false
+	}
+	var prevAct problem.TourActivity = vr.Start()
+	if i > 0 {
+		prevAct = acts[i-1]
+	}
+	var nextAct problem.TourActivity = vr.End()
+	for _, a := range acts[i+1:] {
+		if !isMoved[a] {
+			nextAct = a
+			break
+		}
+	}
+	departureTime, _ := vr.DepartureTime()
+	iContext := constraint.NewJobInsertionContext(vr, jobAct.Job(), vr.Vehicle(), vr.Driver(), departureTime)
+	iContext.SetActivityContext(constraint.NewActivityContext(i, 0., 0.))
+	if _, ok := act.(*activity.DeliverShipment); ok {
+		for k, a := range acts[:i] {
+			if pickup, ok := a.(*activity.PickupShipment); ok && pickup.Job() == jobAct.Job() {
+				iContext.SetRelatedActivityContext(constraint.NewActivityContext(k, pickup.ArrTime(), pickup.EndTime()))
+			}
+		}
+	} else if !e.constraintManager.HardRouteConstraintsFulfilled(iContext) {
+		return false
+	}
+	return e.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, act, nextAct, prevAct.EndTime()) == constraint.Fulfilled
+}
+
+// setActivities replaces the activities of vr by acts and updates its states.
+func (e *routeEvaluator) setActivities(vr *route.VehicleRoute, acts []problem.TourActivity) {
+	tourActivities := vr.TourActivities()
+	for _, job := range tourActivities.Jobs() {
+		tourActivities.RemoveJob(job)
+	}
+	for _, act := range acts {
+		tourActivities.AddActivityToEnd(act)
+	}
+	e.updateRoute(vr)
+}
+
+func (e *routeEvaluator) updateRoute(vr *route.VehicleRoute) {
+	if acts := vr.Activities(); !vr.Vehicle().IsReturnToDepot() && len(acts) > 0 {
+		vr.End().SetLocation(acts[len(acts)-1].Location())
+	}
+	e.stateManager.UpdateRoute(vr)
+}
+
+// isMovable reports whether the local search may reorder the activities of vr. Routes with breaks are left as
+// they are, since breaks do not have a location of their own.
+func isMovable(vr *route.VehicleRoute) bool {
+	if vr.IsEmpty() {
+		return false
+	}
+	for _, act := range vr.Activities() {
+		if _, ok := act.(*activity.BreakActivity); ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package localsearch
+
+import (
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vrp"
+)
+
+// deliveredLoadStateName names the state holding the load of the service deliveries up to and including an activity.
+const deliveredLoadStateName = "localsearch_delivered_load"
+
+// walkLimit is the maximum length of a segment whose activities are checked one by one. Of longer segments, i.e.
+// route tails, only the first activity is checked against the latest operation start time cached for its route.
+const walkLimit = 3
+
+var _ state.ActivityVisitor = (*updateDeliveredLoad)(nil)
+
+// updateDeliveredLoad stores the load of the service deliveries up to and including an activity. Together with
+// the loads cached by the StateManager it yields the load at the beginning of a route whose segments change.
+type updateDeliveredLoad struct {
+	stateManager *state.StateManager
+	id           state.StateId
+	load         *problem.Capacity
+}
+
+func (u *updateDeliveredLoad) Begin(vehicleRoute *route.VehicleRoute) {
+	u.load = problem.NewCapacity(nil)
+}
+
+func (u *updateDeliveredLoad) Visit(act problem.TourActivity) {
+	if _, ok := act.(*activity.DeliverService); ok {
+		u.load = problem.AddUp(u.load, problem.Invert(act.Size()))
+	}
+	u.stateManager.PutActivityState(act, u.id, u.load)
+}
+
+func (u *updateDeliveredLoad) Finish() {}
+
+// segmentExchangeEvaluator estimates exchanges of segments between two routes in constant time based on the
+// states cached by the StateManager: loads, maximum loads, latest operation start times and arrival and end times.
+// Times are exact if both routes are served by vehicles of the same kind, otherwise the estimation may be wrong,
+// which is why moves have to be evaluated exactly before they are applied.
+type segmentExchangeEvaluator struct {
+	vrp           *vrp.VehicleRoutingProblem
+	stateManager  *state.StateManager
+	deliveredLoad state.StateId
+}
+
+func newSegmentExchangeEvaluator(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager) *segmentExchangeEvaluator {
+	id, exists := stateManager.LookupStateId(deliveredLoadStateName)
+	if !exists {
+		id = stateManager.CreateStateId(deliveredLoadStateName)
+		stateManager.AddActivityVisitor(&updateDeliveredLoad{stateManager: stateManager, id: id})
+	}
+	return &segmentExchangeEvaluator{
+		vrp:           vrp,
+		stateManager:  stateManager,
+		deliveredLoad: id,
+	}
+}
+
+// estimate returns the estimated change of the transport costs if the activities [i,i+k) of r1 are exchanged with
+// the activities [j,j+l) of r2, and whether the exchange looks feasible.
+func (e *segmentExchangeEvaluator) estimate(r1 *route.VehicleRoute, acts1 []problem.TourActivity, i, k int,
+	r2 *route.VehicleRoute, acts2 []problem.TourActivity, j, l int) (float64, bool) {
+	if !e.isFeasible(r1, acts1, i, k, r2, acts2, j, l) || !e.isFeasible(r2, acts2, j, l, r1, acts1, i, k) {
+		return 0., false
+	}
+	return e.costsDelta(r1, acts1, i, k, acts2[j:j+l]) + e.costsDelta(r2, acts2, j, l, acts1[i:i+k]), true
+}
+
+// isFeasible checks whether r can serve its activities with [i,i+k) replaced by the activities [j,j+l) of q.
+func (e *segmentExchangeEvaluator) isFeasible(r *route.VehicleRoute, acts []problem.TourActivity, i, k int,
+	q *route.VehicleRoute, qActs []problem.TourActivity, j, l int) bool {
+	sm := e.stateManager
+	capacity := r.Vehicle().Type().CapacityDimensions()
+	// the deliveries of the segments change the load at the beginning, which shifts the load at every activity
+	loadAtBeginning := sm.LoadAtBeginning(r)
+	newLoadAtBeginning := problem.AddUp(
+		problem.Subtract(loadAtBeginning, problem.Subtract(e.delivered(acts, i+k-1), e.delivered(acts, i-1))),
+		problem.Subtract(e.delivered(qActs, j+l-1), e.delivered(qActs, j-1)))
+	if !newLoadAtBeginning.IsLessOrEqual(capacity) {
+		return false
+	}
+	shift := problem.Subtract(newLoadAtBeginning, loadAtBeginning)
+	if i > 0 && !problem.AddUp(sm.PastMaxLoad(acts[i-1]), shift).IsLessOrEqual(capacity) {
+		return false
+	}
+	entering := problem.AddUp(e.load(r, acts, i-1), shift)
+	if l > 0 {
+		segmentMaxLoad := problem.NewCapacity(nil)
+		if j+l == len(qActs) {
+			segmentMaxLoad = sm.FutureMaxLoad(qActs[j])
+		} else {
+			for _, act := range qActs[j : j+l] {
+				segmentMaxLoad = problem.Max(segmentMaxLoad, sm.Load(act))
+			}
+		}
+		if !problem.AddUp(entering, problem.Subtract(segmentMaxLoad, e.load(q, qActs, j-1))).IsLessOrEqual(capacity) {
+			return false
+		}
+	}
+	leaving := problem.AddUp(entering, problem.Subtract(e.load(q, qActs, j+l-1), e.load(q, qActs, j-1)))
+	if i+k < len(acts) &&
+		!problem.AddUp(leaving, problem.Subtract(sm.FutureMaxLoad(acts[i+k]), e.load(r, acts, i+k-1))).IsLessOrEqual(capacity) {
+		return false
+	}
+
+	transportCosts, activityCosts := e.vrp.TransportCosts(), e.vrp.ActivityCosts()
+	v, d := r.Vehicle(), r.Driver()
+	location, time := e.location(r, acts, i-1), e.endTime(r, acts, i-1)
+	segment := qActs[j : j+l]
+	if j+l == len(qActs) && l > walkLimit {
+		// the tail of q, which is feasible as long as its first activity starts in time
+		arrTime := time + transportCosts.TransportTime(location, segment[0].Location(), time, d, v)
+		return arrTime <= sm.LatestOperationStartTime(segment[0])
+	}
+	for _, act := range segment {
+		arrTime := time + transportCosts.TransportTime(location, act.Location(), time, d, v)
+		if arrTime > act.TheoreticalLatestOperationStartTime() {
+			return false
+		}
+		time = max(arrTime, act.TheoreticalEarliestOperationStartTime()) + activityCosts.ActivityDuration(act, arrTime, d, v)
+		location = act.Location()
+	}
+	if i+k < len(acts) {
+		next := acts[i+k]
+		return time+transportCosts.TransportTime(location, next.Location(), time, d, v) <= sm.LatestOperationStartTime(next)
+	}
+	if v.IsReturnToDepot() {
+		time += transportCosts.TransportTime(location, r.End().Location(), time, d, v)
+	}
+	return time <= v.LatestArrival()
+}
+
+// costsDelta estimates the change of the transport costs of r if [i,i+k) is replaced by segment. The edges within
+// segments longer than walkLimit are assumed to cost the same in both routes.
+func (e *segmentExchangeEvaluator) costsDelta(r *route.VehicleRoute, acts []problem.TourActivity, i, k int,
+	segment []problem.TourActivity) float64 {
+	from, time := e.location(r, acts, i-1), e.endTime(r, acts, i-1)
+	var to *problem.Location
+	if i+k < len(acts) {
+		to = acts[i+k].Location()
+	} else if r.Vehicle().IsReturnToDepot() {
+		to = r.End().Location()
+	}
+	return e.pathCosts(r, from, segment, to, time) - e.pathCosts(r, from, acts[i:i+k], to, time)
+}
+
+// pathCosts returns the transport costs from from via the activities of segment to to, where to is nil if the
+// path ends at the last activity.
+func (e *segmentExchangeEvaluator) pathCosts(r *route.VehicleRoute, from *problem.Location, segment []problem.TourActivity,
+	to *problem.Location, time float64) float64 {
+	transportCosts := e.vrp.TransportCosts()
+	v, d := r.Vehicle(), r.Driver()
+	if len(segment) == 0 {
+		if to == nil {
+			return 0.
+		}
+		return transportCosts.TransportCost(from, to, time, d, v)
+	}
+	costs := transportCosts.TransportCost(from, segment[0].Location(), time, d, v)
+	if len(segment) <= walkLimit {
+		for n := 1; n < len(segment); n++ {
+			costs += transportCosts.TransportCost(segment[n-1].Location(), segment[n].Location(), time, d, v)
+		}
+	}
+	if to != nil {
+		costs += transportCosts.TransportCost(segment[len(segment)-1].Location(), to, time, d, v)
+	}
+	return costs
+}
+
+// load returns the load after acts[p] or, if p is -1, at the beginning of r.
+func (e *segmentExchangeEvaluator) load(r *route.VehicleRoute, acts []problem.TourActivity, p int) *problem.Capacity {
+	if p < 0 {
+		return e.stateManager.LoadAtBeginning(r)
+	}
+	return e.stateManager.Load(acts[p])
+}
+
+// delivered returns the load of the service deliveries up to acts[p].
+func (e *segmentExchangeEvaluator) delivered(acts []problem.TourActivity, p int) *problem.Capacity {
+	if p < 0 {
+		return problem.NewCapacity(nil)
+	}
+	if s, ok := e.stateManager.ActivityState(acts[p], e.deliveredLoad); ok {
+		return s.(*problem.Capacity)
+	}
+	return problem.NewCapacity(nil)
+}
+
+func (e *segmentExchangeEvaluator) location(r *route.VehicleRoute, acts []problem.TourActivity, p int) *problem.Location {
+	if p < 0 {
+		return r.Start().Location()
+	}
+	return acts[p].Location()
+}
+
+func (e *segmentExchangeEvaluator) endTime(r *route.VehicleRoute, acts []problem.TourActivity, p int) float64 {
+	if p < 0 {
+		departureTime, _ := r.DepartureTime()
+		return departureTime
+	}
+	return acts[p].EndTime()
+}
@@ -0,0 +1,90 @@
+package module
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/ruin"
+	"gsprit/problem"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+)
+
+var _ algorithm.SearchStrategyModule = (*RuinAndRecreateModule)(nil)
+
+// RuinAndRecreateModule removes jobs from a solution with a ruin strategy and reinserts them
+// with an insertion strategy.
+type RuinAndRecreateModule struct {
+	moduleName string
+	ruin       ruin.RuinStrategy
+	insertion  recreate.InsertionStrategy
+}
+
+func NewRuinAndRecreateModule(moduleName string, insertion recreate.InsertionStrategy, ruin ruin.RuinStrategy) *RuinAndRecreateModule {
+	return &RuinAndRecreateModule{
+		moduleName: moduleName,
+		insertion:  insertion,
+		ruin:       ruin,
+	}
+}
+
+// RunAndGetSolution ruins and recreates the given solution in place. SearchStrategy.Run hands over a copy,
+// thus the solution the copy was made from stays untouched.
+func (m *RuinAndRecreateModule) RunAndGetSolution(previousSolution *solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	ruinedJobs := m.ruin.Ruin(previousSolution.Routes())
+
+	jobsToInsert := make([]problem.Job, 0, len(ruinedJobs)+len(previousSolution.UnassignedJobs()))
+	seen := make(map[problem.Job]bool)
+	for _, j := range ruinedJobs {
+		if !seen[j] {
+			seen[j] = true
+			jobsToInsert = append(jobsToInsert, j)
+		}
+	}
+	for _, j := range previousSolution.UnassignedJobs() {
+		if !seen[j] {
+			seen[j] = true
+			jobsToInsert = append(jobsToInsert, j)
+		}
+	}
+
+	vehicleRoutes := previousSolution.Routes()
+	badJobs := m.insertion.InsertJobs(&vehicleRoutes, jobsToInsert)
+
+	nonEmptyRoutes := make([]*route.VehicleRoute, 0, len(vehicleRoutes))
+	for _, r := range vehicleRoutes {
+		if !r.IsEmpty() {
+			nonEmptyRoutes = append(nonEmptyRoutes, r)
+		}
+	}
+	previousSolution.SetRoutes(nonEmptyRoutes)
+	previousSolution.SetUnassignedJobs(badJobs)
+	return previousSolution
+}
+
+func (m *RuinAndRecreateModule) Name() string {
+	return m.moduleName
+}
+
+// AddModuleListener registers the listener with the ruin strategy if it is a ruin.RuinListener
+// and with the insertion strategy if it listens to insertion events.
+func (m *RuinAndRecreateModule) AddModuleListener(moduleListener algorithm.SearchStrategyModuleListener) {
+	if l, ok := moduleListener.(ruin.RuinListener); ok {
+		m.ruin.AddListener(l)
+	}
+	if recreate.IsInsertionListener(moduleListener) {
+		m.insertion.AddListener(moduleListener)
+	}
+}
+
+func (m *RuinAndRecreateModule) Insertion() recreate.InsertionStrategy {
+	return m.insertion
+}
+
+func (m *RuinAndRecreateModule) Ruin() ruin.RuinStrategy {
+	return m.ruin
+}
+
+func (m *RuinAndRecreateModule) String() string {
+	return fmt.Sprintf("[name=%s][ruin=%v][insertion=%v]", m.moduleName, m.ruin, m.insertion)
+}
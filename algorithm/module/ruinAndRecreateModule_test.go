@@ -0,0 +1,129 @@
+package module
+
+import (
+	"testing"
+
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/ruin"
+	"gsprit/problem"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vehicle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type removeAllRuin struct {
+	*ruin.RuinListeners
+}
+
+func (r *removeAllRuin) Ruin(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	r.RuinStarts(vehicleRoutes)
+	removed := make([]problem.Job, 0)
+	for _, vr := range vehicleRoutes {
+		for _, act := range vr.Activities() {
+			j := act.(problem.JobActivity).Job()
+			if vr.TourActivities().RemoveJob(j) {
+				r.Removed(j, vr)
+				removed = append(removed, j)
+			}
+		}
+	}
+	r.RuinEnds(vehicleRoutes, removed)
+	return removed
+}
+
+type rejectingInsertion struct {
+	*recreate.InsertionListeners
+	reject problem.Job
+}
+
+func (i *rejectingInsertion) InsertJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job {
+	i.InformInsertionStarts(*vehicleRoutes, unassignedJobs)
+	badJobs := make([]problem.Job, 0)
+	for _, j := range unassignedJobs {
+		if j == i.reject {
+			badJobs = append(badJobs, j)
+			continue
+		}
+		(*vehicleRoutes)[0].TourActivities().AddActivityToEnd(activity.NewServiceActivity(j.(*job.Service)))
+		i.InformJobInserted(j, (*vehicleRoutes)[0], 0.)
+	}
+	i.InformInsertionEnds(*vehicleRoutes, badJobs)
+	return badJobs
+}
+
+type recordingListener struct {
+	removed  []problem.Job
+	inserted []problem.Job
+}
+
+func (l *recordingListener) RuinStarts(routes []*route.VehicleRoute) {}
+
+func (l *recordingListener) RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job) {}
+
+func (l *recordingListener) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	l.removed = append(l.removed, job)
+}
+
+func (l *recordingListener) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	l.inserted = append(l.inserted, job)
+}
+
+func newService(id string) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).SetLocation(problem.NewLocationWithCoordinate(1, 1)).Build()
+}
+
+func newSolution(services ...*job.Service) *solution.VehicleRoutingProblemSolution {
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	b := route.NewVehicleRouteBuilder(v, driver.NewNoDriver())
+	for _, s := range services {
+		b.AddService(s)
+	}
+	return solution.NewVehicleRoutingProblemSolution([]*route.VehicleRoute{b.Build()}, 0.)
+}
+
+func TestRuinAndRecreate_RejectedJobsMustBeUnassigned(t *testing.T) {
+	s1, s2, s3 := newService("s1"), newService("s2"), newService("s3")
+	sol := newSolution(s1, s2)
+	sol.SetUnassignedJobs([]problem.Job{s3})
+
+	insertion := &rejectingInsertion{InsertionListeners: recreate.NewInsertionListeners(), reject: s2}
+	m := NewRuinAndRecreateModule("rr", insertion, &removeAllRuin{RuinListeners: ruin.NewRuinListeners()})
+	result := m.RunAndGetSolution(sol)
+
+	assert.Equal(t, []problem.Job{s2}, result.UnassignedJobs())
+	assert.Len(t, result.Routes(), 1)
+	assert.True(t, result.Routes()[0].TourActivities().ServesJob(s1))
+	assert.True(t, result.Routes()[0].TourActivities().ServesJob(s3))
+	assert.False(t, result.Routes()[0].TourActivities().ServesJob(s2))
+}
+
+func TestRuinAndRecreate_EmptyRoutesMustBeRemoved(t *testing.T) {
+	s1 := newService("s1")
+	sol := newSolution(s1)
+
+	insertion := &rejectingInsertion{InsertionListeners: recreate.NewInsertionListeners(), reject: s1}
+	m := NewRuinAndRecreateModule("rr", insertion, &removeAllRuin{RuinListeners: ruin.NewRuinListeners()})
+	result := m.RunAndGetSolution(sol)
+
+	assert.Empty(t, result.Routes())
+	assert.Equal(t, []problem.Job{s1}, result.UnassignedJobs())
+}
+
+func TestRuinAndRecreate_ModuleListenersMustBeInformed(t *testing.T) {
+	s1, s2 := newService("s1"), newService("s2")
+	sol := newSolution(s1, s2)
+
+	insertion := &rejectingInsertion{InsertionListeners: recreate.NewInsertionListeners()}
+	m := NewRuinAndRecreateModule("rr", insertion, &removeAllRuin{RuinListeners: ruin.NewRuinListeners()})
+	l := &recordingListener{}
+	m.AddModuleListener(l)
+	m.RunAndGetSolution(sol)
+
+	assert.Equal(t, []problem.Job{s1, s2}, l.removed)
+	assert.Equal(t, []problem.Job{s1, s2}, l.inserted)
+}
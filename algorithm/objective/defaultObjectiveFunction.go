@@ -0,0 +1,132 @@
+package objective
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/driver"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+// Components are the costs of a solution broken down by their origin.
+type Components struct {
+	Fixed             float64
+	Transport         float64
+	Activity          float64
+	UnassignedPenalty float64
+}
+
+// Total returns the sum of all components.
+func (c Components) Total() float64 {
+	return c.Fixed + c.Transport + c.Activity + c.UnassignedPenalty
+}
+
+func (c Components) String() string {
+	return fmt.Sprintf("[fixed=%.2f][transport=%.2f][activity=%.2f][unassignedPenalty=%.2f][total=%.2f]",
+		c.Fixed, c.Transport, c.Activity, c.UnassignedPenalty, c.Total())
+}
+
+var _ solution.SolutionCostCalculator = (*DefaultObjectiveFunction)(nil)
+
+// DefaultObjectiveFunction calculates the costs of a solution as the sum of the fixed costs of the vehicles used,
+// the transport and activity costs of all routes and a penalty for every unassigned job.
+//
+// The penalty of a job is scaled by its priority: a job with priority p costs (11-p) times the unassigned job
+// penalty, i.e. leaving a job with priority 1 (very high) unassigned is ten times as expensive as one with
+// priority 10 (very low).
+//
+// Times are recalculated along each route rather than read from its activities, thus the costs are correct
+// even if the arrival and end times of the activities are outdated.
+type DefaultObjectiveFunction struct {
+	vrp                  *vrp.VehicleRoutingProblem
+	unassignedJobPenalty float64
+}
+
+// NewDefaultObjectiveFunction creates an objective function whose unassigned job penalty is twice the maximum
+// transport costs between any two locations of the jobs, so that serving a job is usually cheaper than not.
+func NewDefaultObjectiveFunction(vrp *vrp.VehicleRoutingProblem) *DefaultObjectiveFunction {
+	return &DefaultObjectiveFunction{
+		vrp:                  vrp,
+		unassignedJobPenalty: 2. * maxTransportCosts(vrp),
+	}
+}
+
+// maxTransportCosts returns the maximum transport costs between two locations of the jobs of vrp.
+func maxTransportCosts(vrp *vrp.VehicleRoutingProblem) float64 {
+	vehicles := vrp.Vehicles()
+	if len(vehicles) == 0 {
+		return 0.
+	}
+	locations := make([]*problem.Location, 0)
+	seen := make(map[*problem.Location]bool)
+	for _, job := range vrp.Jobs() {
+		for _, act := range job.Activities() {
+			if l := act.Location(); l != nil && !seen[l] {
+				seen[l] = true
+				locations = append(locations, l)
+			}
+		}
+	}
+	maxCosts := 0.
+	d := driver.NewNoDriver()
+	for _, from := range locations {
+		for _, to := range locations {
+			maxCosts = math.Max(maxCosts, vrp.TransportCosts().TransportCost(from, to, 0., d, vehicles[0]))
+		}
+	}
+	return maxCosts
+}
+
+// SetUnassignedJobPenalty sets the penalty of an unassigned job with the lowest priority.
+func (o *DefaultObjectiveFunction) SetUnassignedJobPenalty(penalty float64) {
+	o.unassignedJobPenalty = penalty
+}
+
+func (o *DefaultObjectiveFunction) UnassignedJobPenalty() float64 {
+	return o.unassignedJobPenalty
+}
+
+func (o *DefaultObjectiveFunction) Costs(sol *solution.VehicleRoutingProblemSolution) float64 {
+	return o.Components(sol).Total()
+}
+
+// Components returns the costs of sol broken down by their origin.
+func (o *DefaultObjectiveFunction) Components(sol *solution.VehicleRoutingProblemSolution) Components {
+	var res Components
+	for _, vr := range sol.Routes() {
+		if vr.IsEmpty() {
+			continue
+		}
+		res.Fixed += vr.Vehicle().Type().VehicleCostParams().Fix()
+		transport, act := o.routeCosts(vr)
+		res.Transport += transport
+		res.Activity += act
+	}
+	for _, job := range sol.UnassignedJobs() {
+		res.UnassignedPenalty += o.unassignedJobPenalty * float64(11-job.Priority())
+	}
+	return res
+}
+
+// routeCosts returns the transport and activity costs of vr.
+func (o *DefaultObjectiveFunction) routeCosts(vr *route.VehicleRoute) (float64, float64) {
+	transportCosts, activityCosts := o.vrp.TransportCosts(), o.vrp.ActivityCosts()
+	v, d := vr.Vehicle(), vr.Driver()
+	transport, activity := 0., 0.
+	prevLocation := vr.Start().Location()
+	prevEndTime, _ := vr.DepartureTime()
+	for _, act := range vr.Activities() {
+		transport += transportCosts.TransportCost(prevLocation, act.Location(), prevEndTime, d, v)
+		arrTime := prevEndTime + transportCosts.TransportTime(prevLocation, act.Location(), prevEndTime, d, v)
+		activity += activityCosts.ActivityCost(act, arrTime, d, v)
+		prevLocation = act.Location()
+		prevEndTime = math.Max(arrTime, act.TheoreticalEarliestOperationStartTime()) + activityCosts.ActivityDuration(act, arrTime, d, v)
+	}
+	// a vehicle not returning to its depot ends its route right after the last activity
+	if v.IsReturnToDepot() {
+		transport += transportCosts.TransportCost(prevLocation, vr.End().Location(), prevEndTime, d, v)
+	}
+	return transport, activity
+}
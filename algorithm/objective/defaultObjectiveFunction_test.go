@@ -0,0 +1,58 @@
+package objective
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newService(id string, x float64, serviceTime float64) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).SetLocation(problem.NewLocationWithCoordinate(x, 0)).
+		SetServiceTime(serviceTime).Build()
+}
+
+func TestDefaultObjectiveFunction_ShouldAddUpAllComponents(t *testing.T) {
+	s1, s2 := newService("s1", 10, 5), newService("s2", 20, 0)
+	unassigned := job.NewServiceBuilder[*job.Service]("s3").SetLocation(problem.NewLocationWithCoordinate(30, 0)).
+		SetPriority(1).Build()
+	vt := vehicle.NewVehicleTypeBuilder("type").SetFixedCost(100.).SetCostPerServiceTime(2.).Build()
+	v := vehicle.NewVehicleBuilder("v").SetType(vt).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(s1).AddJob(s2).AddJob(unassigned).Build()
+	r := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(s1).AddService(s2).Build()
+	sol := solution.NewVehicleRoutingProblemSolutionWithJobs([]*route.VehicleRoute{r}, []problem.Job{unassigned}, 0.)
+
+	objective := NewDefaultObjectiveFunction(p)
+	// the farthest locations of the jobs are 20 apart
+	assert.Equal(t, 40., objective.UnassignedJobPenalty())
+	objective.SetUnassignedJobPenalty(1.)
+
+	c := objective.Components(sol)
+	assert.Equal(t, 100., c.Fixed)
+	assert.Equal(t, 40., c.Transport)
+	assert.Equal(t, 10., c.Activity)
+	assert.Equal(t, 10., c.UnassignedPenalty)
+	assert.Equal(t, 160., objective.Costs(sol))
+}
+
+func TestDefaultObjectiveFunction_OpenRoutesShouldEndAtLastActivity(t *testing.T) {
+	s1 := newService("s1", 10, 0)
+	vt := vehicle.NewVehicleTypeBuilder("type").Build()
+	v := vehicle.NewVehicleBuilder("v").SetType(vt).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		SetReturnToDepot(false).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(s1).Build()
+	r := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(s1).Build()
+	empty := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).Build()
+	sol := solution.NewVehicleRoutingProblemSolution([]*route.VehicleRoute{r, empty}, 0.)
+
+	c := NewDefaultObjectiveFunction(p).Components(sol)
+	assert.Equal(t, Components{Transport: 10.}, c)
+}
@@ -0,0 +1,225 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"gsprit/util"
+	"math/rand/v2"
+	"sort"
+)
+
+var _ InsertionStartsListener = (*state.StateManager)(nil)
+var _ JobInsertedListener = (*state.StateManager)(nil)
+
+type jobsInserter interface {
+	insertUnassignedJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job
+}
+
+// AbstractInsertionStrategy implements what all insertion strategies have in common, i.e. listener handling,
+// the evaluation of existing and new routes and the insertion itself. The order in which jobs are inserted
+// is delegated to spi. Insertions have to fulfill the constraints of the ConstraintManager, which are
+// evaluated based on the states cached by the StateManager. The StateManager is therefore registered as
+// the first insertion listener.
+//
+// New routes are opened with the vehicles the VehicleFleetManager reports available. With an infinite fleet
+// every vehicle can be used over and over again, with a finite fleet only vehicles that do not serve a route yet.
+// Unless disabled, inserting a job into a route also considers replacing the vehicle of the route by an available
+// vehicle of another kind. Whether and at which costs a vehicle may take over a route is cached until the route
+// changes.
+type AbstractInsertionStrategy struct {
+	vrp                  *vrp.VehicleRoutingProblem
+	stateManager         *state.StateManager
+	constraintManager    *constraint.ConstraintManager
+	insertionListeners   *InsertionListeners
+	inserter             *Inserter
+	jobCalculator        JobInsertionCostsCalculator
+	random               *rand.Rand
+	fleetManager         *VehicleFleetManager
+	vehicleSwitchAllowed bool
+	switchCache          *vehicleSwitchCache
+	spi                  jobsInserter
+}
+
+func newAbstractInsertionStrategy(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager, constraintManager *constraint.ConstraintManager,
+	spi jobsInserter) AbstractInsertionStrategy {
+	// the states have to be up to date before anyone else gets informed about an insertion
+	insertionListeners := NewInsertionListeners()
+	insertionListeners.AddListener(stateManager)
+	fleetManager := NewVehicleFleetManager(vrp)
+	insertionListeners.AddListener(fleetManager)
+	switchCache := newVehicleSwitchCache()
+	insertionListeners.AddListener(switchCache)
+	return AbstractInsertionStrategy{
+		vrp:                vrp,
+		stateManager:       stateManager,
+		constraintManager:  constraintManager,
+		insertionListeners: insertionListeners,
+		inserter:           NewInserter(vrp),
+		jobCalculator: NewJobCalculatorSwitcher(NewServiceInsertionCalculator(vrp, constraintManager),
+			NewShipmentInsertionCalculator(vrp, constraintManager)),
+		random:               util.NewRandom(util.DefaultSeed),
+		fleetManager:         fleetManager,
+		vehicleSwitchAllowed: true,
+		switchCache:          switchCache,
+		spi:                  spi,
+	}
+}
+
+func (s *AbstractInsertionStrategy) InsertJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job {
+	s.insertionListeners.InformInsertionStarts(*vehicleRoutes, unassignedJobs)
+	badJobs := s.spi.insertUnassignedJobs(vehicleRoutes, unassignedJobs)
+	s.insertionListeners.InformInsertionEnds(*vehicleRoutes, badJobs)
+	return badJobs
+}
+
+func (s *AbstractInsertionStrategy) StateManager() *state.StateManager {
+	return s.stateManager
+}
+
+func (s *AbstractInsertionStrategy) ConstraintManager() *constraint.ConstraintManager {
+	return s.constraintManager
+}
+
+func (s *AbstractInsertionStrategy) VehicleFleetManager() *VehicleFleetManager {
+	return s.fleetManager
+}
+
+// SetVehicleSwitchAllowed enables or disables replacing the vehicle of a route when inserting a job.
+func (s *AbstractInsertionStrategy) SetVehicleSwitchAllowed(vehicleSwitchAllowed bool) {
+	s.vehicleSwitchAllowed = vehicleSwitchAllowed
+}
+
+func (s *AbstractInsertionStrategy) SetRandom(r *rand.Rand) {
+	s.random = r
+}
+
+// SetJobInsertionCostsCalculator replaces the calculator evaluating insertions.
+func (s *AbstractInsertionStrategy) SetJobInsertionCostsCalculator(jobCalculator JobInsertionCostsCalculator) {
+	s.jobCalculator = jobCalculator
+}
+
+func (s *AbstractInsertionStrategy) JobInsertionCostsCalculator() JobInsertionCostsCalculator {
+	return s.jobCalculator
+}
+
+func (s *AbstractInsertionStrategy) AddListener(l InsertionListener) {
+	s.insertionListeners.AddListener(l)
+}
+
+func (s *AbstractInsertionStrategy) RemoveListener(l InsertionListener) {
+	s.insertionListeners.RemoveListener(l)
+}
+
+func (s *AbstractInsertionStrategy) Listeners() []InsertionListener {
+	return s.insertionListeners.Listeners()
+}
+
+// routeInsertion evaluates the insertion of job into an existing route, served either by its vehicle or, if
+// vehicle switches are allowed, by one of the available vehicles.
+func (s *AbstractInsertionStrategy) routeInsertion(job problem.Job, vehicleRoute *route.VehicleRoute, bestKnownCosts float64) *InsertionData {
+	departureTime, err := vehicleRoute.DepartureTime()
+	if err != nil {
+		return NewNoInsertionFound()
+	}
+	best := s.jobCalculator.InsertionData(vehicleRoute, job, vehicleRoute.Vehicle(), departureTime, vehicleRoute.Driver(), bestKnownCosts)
+	if !s.vehicleSwitchAllowed {
+		return best
+	}
+	bestCosts := min(bestKnownCosts, best.InsertionCost())
+	for _, v := range s.switchVehicles(vehicleRoute) {
+		data := s.vehicleSwitchInsertion(job, vehicleRoute, v, bestCosts)
+		if !data.IsNoInsertionFound() && data.InsertionCost() < bestCosts {
+			best = data
+			bestCosts = data.InsertionCost()
+		}
+	}
+	return best
+}
+
+// newRouteInsertion evaluates the insertion of job into a new route opened with one of the available vehicles.
+func (s *AbstractInsertionStrategy) newRouteInsertion(job problem.Job, bestKnownCosts float64) *InsertionData {
+	best := NewNoInsertionFound()
+	bestCosts := bestKnownCosts
+	for _, data := range s.newRouteInsertions(job, bestKnownCosts) {
+		if data.InsertionCost() < bestCosts {
+			best = data
+			bestCosts = data.InsertionCost()
+		}
+	}
+	return best
+}
+
+// newRouteInsertions evaluates the insertion of job into a new route for every available vehicle and
+// returns the feasible insertions.
+func (s *AbstractInsertionStrategy) newRouteInsertions(job problem.Job, bestKnownCosts float64) []*InsertionData {
+	insertions := make([]*InsertionData, 0)
+	emptyRoute := route.EmptyRoute()
+	for _, v := range s.fleetManager.AvailableVehicles() {
+		data := s.jobCalculator.InsertionData(emptyRoute, job, v, v.EarliestDeparture(), emptyRoute.Driver(), bestKnownCosts)
+		if !data.IsNoInsertionFound() {
+			insertions = append(insertions, data)
+		}
+	}
+	return insertions
+}
+
+// bestInsertion returns the cheapest insertion of job into either an existing or a new route. The route
+// returned is nil if no insertion has been found; for new routes it is an empty route not yet contained in
+// vehicleRoutes.
+func (s *AbstractInsertionStrategy) bestInsertion(job problem.Job, vehicleRoutes []*route.VehicleRoute) (*InsertionData, *route.VehicleRoute) {
+	best := NewNoInsertionFound()
+	var bestRoute *route.VehicleRoute
+	for _, vr := range vehicleRoutes {
+		data := s.routeInsertion(job, vr, best.InsertionCost())
+		if !data.IsNoInsertionFound() && data.InsertionCost() < best.InsertionCost() {
+			best, bestRoute = data, vr
+		}
+	}
+	data := s.newRouteInsertion(job, best.InsertionCost())
+	if !data.IsNoInsertionFound() && data.InsertionCost() < best.InsertionCost() {
+		best, bestRoute = data, s.newRoute(data)
+	}
+	return best, bestRoute
+}
+
+func (s *AbstractInsertionStrategy) newRoute(insertionData *InsertionData) *route.VehicleRoute {
+	return route.NewVehicleRouteBuilder(insertionData.SelectedVehicle(), insertionData.SelectedDriver()).
+		SetDepartureTime(insertionData.VehicleDepartureTime()).
+		Build()
+}
+
+// insertJob inserts job into inRoute, appends inRoute to vehicleRoutes if it is a new route and informs the listeners.
+func (s *AbstractInsertionStrategy) insertJob(job problem.Job, insertionData *InsertionData, inRoute *route.VehicleRoute, vehicleRoutes *[]*route.VehicleRoute) {
+	isNewRoute := true
+	for _, vr := range *vehicleRoutes {
+		if vr == inRoute {
+			isNewRoute = false
+			break
+		}
+	}
+	if isNewRoute {
+		*vehicleRoutes = append(*vehicleRoutes, inRoute)
+	}
+	oldVehicle := inRoute.Vehicle()
+	s.inserter.InsertJob(job, insertionData, inRoute)
+	if !isNewRoute && inRoute.Vehicle() != oldVehicle {
+		s.insertionListeners.InformVehicleSwitched(inRoute, oldVehicle, inRoute.Vehicle())
+	}
+	s.insertionListeners.InformJobInserted(job, inRoute, insertionData.InsertionCost())
+}
+
+// shuffleAndSortByPriority shuffles jobs and orders them by priority, i.e. jobs with a high priority (a low value) come first.
+func (s *AbstractInsertionStrategy) shuffleAndSortByPriority(jobs []problem.Job) []problem.Job {
+	res := make([]problem.Job, len(jobs))
+	copy(res, jobs)
+	s.random.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Priority() < res[j].Priority()
+	})
+	return res
+}
@@ -0,0 +1,48 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/solution/route/activity"
+	"math"
+)
+
+// ActivityInsertionCostsCalculator calculates the marginal costs of inserting newAct between prevAct and nextAct.
+type ActivityInsertionCostsCalculator interface {
+	Costs(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity, prevActDepTime float64) float64
+}
+
+var _ ActivityInsertionCostsCalculator = (*LocalActivityInsertionCostsCalculator)(nil)
+
+// LocalActivityInsertionCostsCalculator only considers the legs around newAct: the costs of travelling from
+// prevAct via newAct to nextAct plus the activity costs of newAct, minus the costs of travelling directly from
+// prevAct to nextAct. Legs to the end of a route not returning to the depot are free.
+type LocalActivityInsertionCostsCalculator struct {
+	transportCosts cost.VehicleRoutingTransportCosts
+	activityCosts  cost.VehicleRoutingActivityCosts
+}
+
+func NewLocalActivityInsertionCostsCalculator(transportCosts cost.VehicleRoutingTransportCosts,
+	activityCosts cost.VehicleRoutingActivityCosts) *LocalActivityInsertionCostsCalculator {
+	return &LocalActivityInsertionCostsCalculator{
+		transportCosts: transportCosts,
+		activityCosts:  activityCosts,
+	}
+}
+
+func (c *LocalActivityInsertionCostsCalculator) Costs(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) float64 {
+	v, d := iContext.NewVehicle(), iContext.NewDriver()
+	arrTimeAtNewAct := prevActDepTime + c.transportCosts.TransportTime(prevAct.Location(), newAct.Location(), prevActDepTime, d, v)
+	costs := c.transportCosts.TransportCost(prevAct.Location(), newAct.Location(), prevActDepTime, d, v) +
+		c.activityCosts.ActivityCost(newAct, arrTimeAtNewAct, d, v)
+	if _, isEnd := nextAct.(*activity.End); isEnd && !v.IsReturnToDepot() {
+		return costs
+	}
+	endTimeAtNewAct := math.Max(arrTimeAtNewAct, newAct.TheoreticalEarliestOperationStartTime()) +
+		c.activityCosts.ActivityDuration(newAct, arrTimeAtNewAct, d, v)
+	return costs +
+		c.transportCosts.TransportCost(newAct.Location(), nextAct.Location(), endTimeAtNewAct, d, v) -
+		c.transportCosts.TransportCost(prevAct.Location(), nextAct.Location(), prevActDepTime, d, v)
+}
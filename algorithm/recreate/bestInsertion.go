@@ -0,0 +1,40 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ InsertionStrategy = (*BestInsertion)(nil)
+
+// BestInsertion inserts the jobs one after another, each at its cheapest position among all routes.
+// Jobs are processed in random order, jobs with a higher priority first.
+type BestInsertion struct {
+	AbstractInsertionStrategy
+}
+
+func NewBestInsertion(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager, constraintManager *constraint.ConstraintManager) *BestInsertion {
+	res := &BestInsertion{}
+	res.AbstractInsertionStrategy = newAbstractInsertionStrategy(vrp, stateManager, constraintManager, res)
+	return res
+}
+
+func (s *BestInsertion) insertUnassignedJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job {
+	badJobs := make([]problem.Job, 0)
+	for _, job := range s.shuffleAndSortByPriority(unassignedJobs) {
+		data, inRoute := s.bestInsertion(job, *vehicleRoutes)
+		if inRoute == nil {
+			badJobs = append(badJobs, job)
+			continue
+		}
+		s.insertJob(job, data, inRoute, vehicleRoutes)
+	}
+	return badJobs
+}
+
+func (s *BestInsertion) String() string {
+	return "[name=bestInsertion]"
+}
@@ -0,0 +1,45 @@
+package recreate
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+// Inserter carries out an insertion previously evaluated by a JobInsertionCostsCalculator.
+type Inserter struct {
+	vrp *vrp.VehicleRoutingProblem
+}
+
+func NewInserter(vrp *vrp.VehicleRoutingProblem) *Inserter {
+	return &Inserter{
+		vrp: vrp,
+	}
+}
+
+// InsertJob inserts the activities of job into inRoute as described by insertionData. If the selected
+// vehicle differs from the one of the route, the route is assigned to the selected vehicle.
+func (in *Inserter) InsertJob(job problem.Job, insertionData *InsertionData, inRoute *route.VehicleRoute) {
+	if insertionData.IsNoInsertionFound() {
+		panic("cannot insert job " + job.Id() + " since no insertion has been found")
+	}
+	if inRoute.Vehicle() != insertionData.SelectedVehicle() {
+		inRoute.SetVehicleAndDepartureTime(insertionData.SelectedVehicle(), insertionData.VehicleDepartureTime())
+	}
+	acts := in.vrp.JobActivityFactory()(job)
+	for i, tw := range insertionData.TimeWindows() {
+		acts[i].SetTheoreticalEarliestOperationStartTime(tw.Start())
+		acts[i].SetTheoreticalLatestOperationStartTime(tw.End())
+	}
+	if job.JobType().IsShipment() {
+		// the delivery goes first such that the pickup index still refers to the route before the insertion
+		inRoute.TourActivities().AddActivity(insertionData.DeliveryInsertionIndex(), acts[1])
+		inRoute.TourActivities().AddActivity(insertionData.PickupInsertionIndex(), acts[0])
+	} else {
+		inRoute.TourActivities().AddActivity(insertionData.DeliveryInsertionIndex(), acts[0])
+	}
+	if !inRoute.Vehicle().IsReturnToDepot() {
+		activities := inRoute.Activities()
+		inRoute.End().SetLocation(activities[len(activities)-1].Location())
+	}
+}
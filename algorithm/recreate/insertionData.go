@@ -0,0 +1,91 @@
+package recreate
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"math"
+)
+
+// InsertionData is the result of evaluating the insertion of a job into a route.
+// Services are inserted at the delivery insertion index, shipments insert their pickup at the
+// pickup insertion index and their delivery at the delivery insertion index. Both indices refer to
+// the activities of the route before the insertion.
+type InsertionData struct {
+	insertionCost          float64
+	pickupInsertionIndex   int
+	deliveryInsertionIndex int
+	selectedVehicle        problem.Vehicle
+	selectedDriver         problem.Driver
+	vehicleDepartureTime   float64
+	timeWindows            []problem.TimeWindow
+}
+
+var noInsertion = &InsertionData{
+	insertionCost:          math.MaxFloat64,
+	pickupInsertionIndex:   -1,
+	deliveryInsertionIndex: -1,
+}
+
+// NewNoInsertionFound returns the InsertionData indicating that a job cannot be inserted.
+func NewNoInsertionFound() *InsertionData {
+	return noInsertion
+}
+
+func NewInsertionData(insertionCost float64, pickupInsertionIndex, deliveryInsertionIndex int, vehicle problem.Vehicle, driver problem.Driver) *InsertionData {
+	return &InsertionData{
+		insertionCost:          insertionCost,
+		pickupInsertionIndex:   pickupInsertionIndex,
+		deliveryInsertionIndex: deliveryInsertionIndex,
+		selectedVehicle:        vehicle,
+		selectedDriver:         driver,
+	}
+}
+
+func (d *InsertionData) IsNoInsertionFound() bool {
+	return d == noInsertion
+}
+
+func (d *InsertionData) InsertionCost() float64 {
+	return d.insertionCost
+}
+
+func (d *InsertionData) PickupInsertionIndex() int {
+	return d.pickupInsertionIndex
+}
+
+func (d *InsertionData) DeliveryInsertionIndex() int {
+	return d.deliveryInsertionIndex
+}
+
+func (d *InsertionData) SelectedVehicle() problem.Vehicle {
+	return d.selectedVehicle
+}
+
+func (d *InsertionData) SelectedDriver() problem.Driver {
+	return d.selectedDriver
+}
+
+func (d *InsertionData) VehicleDepartureTime() float64 {
+	return d.vehicleDepartureTime
+}
+
+func (d *InsertionData) SetVehicleDepartureTime(departureTime float64) {
+	d.vehicleDepartureTime = departureTime
+}
+
+// TimeWindows returns the time windows selected for the activities of the job, in the order of its activities.
+func (d *InsertionData) TimeWindows() []problem.TimeWindow {
+	return d.timeWindows
+}
+
+func (d *InsertionData) SetTimeWindows(timeWindows []problem.TimeWindow) {
+	d.timeWindows = timeWindows
+}
+
+func (d *InsertionData) String() string {
+	if d.IsNoInsertionFound() {
+		return "[noInsertionFound]"
+	}
+	return fmt.Sprintf("[iCost=%.2f][pickupIndex=%d][deliveryIndex=%d][depTime=%.2f][vehicle=%v][driver=%v]",
+		d.insertionCost, d.pickupInsertionIndex, d.deliveryInsertionIndex, d.vehicleDepartureTime, d.selectedVehicle, d.selectedDriver)
+}
@@ -0,0 +1,116 @@
+package recreate
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"slices"
+)
+
+// InsertionStrategy inserts unassigned jobs into a collection of vehicle routes.
+type InsertionStrategy interface {
+	// InsertJobs inserts the given jobs into vehicleRoutes. New routes are appended to vehicleRoutes.
+	// Jobs that cannot be inserted are returned.
+	InsertJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job
+	AddListener(l InsertionListener)
+	RemoveListener(l InsertionListener)
+	Listeners() []InsertionListener
+}
+
+type InsertionListener interface {
+}
+
+type InsertionStartsListener interface {
+	InsertionListener
+	InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job)
+}
+
+type JobInsertedListener interface {
+	InsertionListener
+	InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64)
+}
+
+// VehicleSwitchedListener is informed whenever an insertion replaces the vehicle of a route.
+type VehicleSwitchedListener interface {
+	InsertionListener
+	InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle)
+}
+
+type InsertionEndsListener interface {
+	InsertionListener
+	InformInsertionEnds(vehicleRoutes []*route.VehicleRoute, badJobs []problem.Job)
+}
+
+// IsInsertionListener reports whether l listens to at least one insertion event.
+func IsInsertionListener(l any) bool {
+	switch l.(type) {
+	case InsertionStartsListener, JobInsertedListener, VehicleSwitchedListener, InsertionEndsListener:
+		return true
+	}
+	return false
+}
+
+type InsertionListeners struct {
+	listeners []InsertionListener
+}
+
+func NewInsertionListeners() *InsertionListeners {
+	return &InsertionListeners{
+		listeners: make([]InsertionListener, 0),
+	}
+}
+
+func (l *InsertionListeners) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	for _, il := range l.listeners {
+		if listener, ok := il.(InsertionStartsListener); ok {
+			listener.InformInsertionStarts(vehicleRoutes, unassignedJobs)
+		}
+	}
+}
+
+func (l *InsertionListeners) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	for _, il := range l.listeners {
+		if listener, ok := il.(JobInsertedListener); ok {
+			listener.InformJobInserted(job, inRoute, additionalCosts)
+		}
+	}
+}
+
+func (l *InsertionListeners) InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle) {
+	for _, il := range l.listeners {
+		if listener, ok := il.(VehicleSwitchedListener); ok {
+			listener.InformVehicleSwitched(vehicleRoute, oldVehicle, newVehicle)
+		}
+	}
+}
+
+func (l *InsertionListeners) InformInsertionEnds(vehicleRoutes []*route.VehicleRoute, badJobs []problem.Job) {
+	for _, il := range l.listeners {
+		if listener, ok := il.(InsertionEndsListener); ok {
+			listener.InformInsertionEnds(vehicleRoutes, badJobs)
+		}
+	}
+}
+
+// AddListener adds listener unless it has been added before, e.g. by another search strategy sharing the
+// insertion strategy.
+func (l *InsertionListeners) AddListener(listener InsertionListener) {
+	if slices.Contains(l.listeners, listener) {
+		return
+	}
+	l.listeners = append(l.listeners, listener)
+}
+
+func (l *InsertionListeners) RemoveListener(listener InsertionListener) {
+	for i, il := range l.listeners {
+		if il == listener {
+			l.listeners = append(l.listeners[:i], l.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *InsertionListeners) Listeners() []InsertionListener {
+	c := make([]InsertionListener, len(l.listeners))
+	copy(c, l.listeners)
+	return c
+}
@@ -0,0 +1,527 @@
+package recreate
+
+import (
+	"math"
+	"testing"
+
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVehicle(id string, capacity int) *vehicle.Vehicle {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, capacity).SetFixedCost(100.).Build()
+	return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+}
+
+func newService(id string, x, y float64) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).
+		SetLocation(problem.NewLocationWithCoordinate(x, y)).
+		AddSizeDimension(0, 1).
+		Build()
+}
+
+func newShipment(id string, fromX, toX float64) *job.Shipment {
+	return job.NewShipmentBuilder(id).
+		SetPickupLocation(problem.NewLocationWithCoordinate(fromX, 0)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(toX, 0)).
+		AddSizeDimension(0, 1).
+		Build()
+}
+
+func newOpenVehicle(id string, capacity int) *vehicle.Vehicle {
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, capacity).SetFixedCost(100.).Build()
+	return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).SetReturnToDepot(false).Build()
+}
+
+func newProblem(fleetSize vrp.FleetSize, vehicles []problem.Vehicle, jobs ...problem.Job) *vrp.VehicleRoutingProblem {
+	return vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).SetFleetSize(fleetSize).AddAllVehicles(vehicles).AddAllJobs(jobs).Build()
+}
+
+func newBestInsertion(p *vrp.VehicleRoutingProblem) *BestInsertion {
+	sm := state.NewStateManager(p)
+	return NewBestInsertion(p, sm, constraint.NewConstraintManager(p, sm))
+}
+
+func newRegretInsertion(p *vrp.VehicleRoutingProblem) *RegretInsertion {
+	sm := state.NewStateManager(p)
+	return NewRegretInsertion(p, sm, constraint.NewConstraintManager(p, sm))
+}
+
+func jobIds(vr *route.VehicleRoute) []string {
+	ids := make([]string, 0)
+	for _, act := range vr.Activities() {
+		ids = append(ids, act.(problem.JobActivity).Job().Id())
+	}
+	return ids
+}
+
+type recordingInsertionListener struct {
+	started  []problem.Job
+	inserted []problem.Job
+	switched []string
+	badJobs  []problem.Job
+}
+
+func (l *recordingInsertionListener) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	l.started = unassignedJobs
+}
+
+func (l *recordingInsertionListener) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	l.inserted = append(l.inserted, job)
+}
+
+func (l *recordingInsertionListener) InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle) {
+	l.switched = append(l.switched, oldVehicle.Id()+"->"+newVehicle.Id())
+}
+
+func (l *recordingInsertionListener) InformInsertionEnds(vehicleRoutes []*route.VehicleRoute, badJobs []problem.Job) {
+	l.badJobs = badJobs
+}
+
+func TestBestInsertion_ShouldInsertAllJobsIntoOneRouteInCheapestOrder(t *testing.T) {
+	s1, s2, s3 := newService("s1", 10, 0), newService("s2", 20, 0), newService("s3", 30, 0)
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newOpenVehicle("v", 3)}, s1, s2, s3)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s2, s3, s1})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, []string{"s1", "s2", "s3"}, jobIds(routes[0]))
+}
+
+func TestBestInsertion_ShouldOpenNewRoutesWhenCapacityIsExceeded(t *testing.T) {
+	s1, s2, s3 := newService("s1", 10, 0), newService("s2", 20, 0), newService("s3", 30, 0)
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newVehicle("v", 2)}, s1, s2, s3)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s1, s2, s3})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, 3, routes[0].TourActivities().JobSize()+routes[1].TourActivities().JobSize())
+}
+
+func TestBestInsertion_WithFiniteFleet_JobsWithoutVehicleMustBeUnassigned(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	p := newProblem(vrp.Finite, []problem.Vehicle{newVehicle("v", 1)}, s1, s2)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s1, s2})
+
+	assert.Len(t, badJobs, 1)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, 1, routes[0].TourActivities().JobSize())
+}
+
+func TestBestInsertion_WithFiniteFleet_ShouldUseEveryVehicleOnce(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	p := newProblem(vrp.Finite, []problem.Vehicle{newVehicle("v1", 1), newVehicle("v2", 1)}, s1, s2)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s1, s2})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 2)
+	assert.NotEqual(t, routes[0].Vehicle().Id(), routes[1].Vehicle().Id())
+}
+
+func TestBestInsertion_ShouldPreferExistingRoutes(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	v := newOpenVehicle("v", 2)
+	p := newProblem(vrp.Infinite, []problem.Vehicle{v}, s1, s2)
+	existing := route.NewVehicleRouteBuilder(v, route.EmptyRoute().Driver()).AddService(s1).Build()
+
+	routes := []*route.VehicleRoute{existing}
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s2})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, []string{"s1", "s2"}, jobIds(existing))
+}
+
+func TestBestInsertion_JobWithUnreachableTimeWindowMustBeUnassigned(t *testing.T) {
+	tw, _ := activity.NewTimeWindow(0., 5.)
+	s1 := job.NewServiceBuilder[*job.Service]("s1").
+		SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+		SetTimeWindow(tw).
+		Build()
+	s2 := newService("s2", 20, 0)
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newVehicle("v", 2)}, s1, s2)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s1, s2})
+
+	assert.Equal(t, []problem.Job{s1}, badJobs)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, []string{"s2"}, jobIds(routes[0]))
+}
+
+func TestBestInsertion_TimeWindowsOfRoutedActivitiesMustBeRespected(t *testing.T) {
+	tw, _ := activity.NewTimeWindow(0., 20.)
+	s1 := job.NewServiceBuilder[*job.Service]("s1").
+		SetLocation(problem.NewLocationWithCoordinate(20, 0)).
+		SetTimeWindow(tw).
+		Build()
+	s2 := newService("s2", 0, 10)
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newVehicle("v", 2)}, s1, s2)
+	v := p.Vehicles()[0]
+	existing := route.NewVehicleRouteBuilder(v, route.EmptyRoute().Driver()).AddService(s1).Build()
+
+	routes := []*route.VehicleRoute{existing}
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s2})
+
+	assert.Empty(t, badJobs)
+	// visiting s2 first would make the vehicle arrive too late at s1
+	assert.Equal(t, []string{"s1", "s2"}, jobIds(existing))
+}
+
+func TestBestInsertion_ShipmentPickupMustPrecedeDeliveryAndRespectCapacity(t *testing.T) {
+	sh1, sh2 := newShipment("sh1", 10, 20), newShipment("sh2", 15, 25)
+	p := newProblem(vrp.Finite, []problem.Vehicle{newVehicle("v", 1)}, sh1, sh2)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{sh1, sh2})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 1)
+	load := 0
+	seen := make(map[problem.Job]bool)
+	for _, act := range routes[0].Activities() {
+		j := act.(problem.JobActivity).Job()
+		if _, isPickup := act.(*activity.PickupShipment); isPickup {
+			assert.False(t, seen[j])
+		} else {
+			assert.True(t, seen[j])
+		}
+		seen[j] = true
+		load += act.Size().Get(0)
+		assert.LessOrEqual(t, load, 1)
+	}
+}
+
+func TestBestInsertion_ListenersMustBeInformed(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	p := newProblem(vrp.Finite, []problem.Vehicle{newVehicle("v", 1)}, s1, s2)
+	insertion := newBestInsertion(p)
+	l := &recordingInsertionListener{}
+	insertion.AddListener(l)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := insertion.InsertJobs(&routes, []problem.Job{s1, s2})
+
+	assert.Equal(t, []problem.Job{s1, s2}, l.started)
+	assert.Len(t, l.inserted, 1)
+	assert.Equal(t, badJobs, l.badJobs)
+}
+
+func TestRegretInsertion_ShouldInsertAllJobs(t *testing.T) {
+	jobs := make([]problem.Job, 0)
+	for i, x := range []float64{10, 20, 30, -10, -20, -30} {
+		jobs = append(jobs, newService(string(rune('a'+i)), x, float64(i)))
+	}
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newVehicle("v", 3)}, jobs...)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newRegretInsertion(p).InsertJobs(&routes, jobs)
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 2)
+	for _, vr := range routes {
+		assert.Equal(t, 3, vr.TourActivities().JobSize())
+		// the routes serve one side of the depot each
+		sign := vr.Activities()[0].Location().Coordinate().X > 0
+		for _, act := range vr.Activities() {
+			assert.Equal(t, sign, act.Location().Coordinate().X > 0)
+		}
+	}
+}
+
+func TestRegretInsertion_ShouldFirstInsertJobsWithFewerOptions(t *testing.T) {
+	t1 := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 1).SetFixedCost(100.).Build()
+	v1 := vehicle.NewVehicleBuilder("v1").SetType(t1).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	v2 := vehicle.NewVehicleBuilder("v2").SetType(t1).SetStartLocation(problem.NewLocationWithCoordinate(100, 0)).Build()
+	// a is cheapest with v1 but can be served by v2 as well, b can only be reached by v1 in time
+	a := newService("a", 1, 0)
+	tw, _ := activity.NewTimeWindow(0., 5.)
+	b := job.NewServiceBuilder[*job.Service]("b").
+		SetLocation(problem.NewLocationWithCoordinate(2, 0)).
+		SetTimeWindow(tw).
+		AddSizeDimension(0, 1).
+		Build()
+	p := newProblem(vrp.Finite, []problem.Vehicle{v1, v2}, a, b)
+
+	routes := make([]*route.VehicleRoute, 0)
+	badJobs := newRegretInsertion(p).InsertJobs(&routes, []problem.Job{a, b})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 2)
+	for _, vr := range routes {
+		if vr.Vehicle().Id() == "v1" {
+			assert.Equal(t, []string{"b"}, jobIds(vr))
+		} else {
+			assert.Equal(t, []string{"a"}, jobIds(vr))
+		}
+	}
+}
+
+func TestRegretInsertion_KMustBeAtLeastTwo(t *testing.T) {
+	p := newProblem(vrp.Infinite, []problem.Vehicle{newVehicle("v", 1)})
+	insertion := newRegretInsertion(p)
+	assert.Panics(t, func() { insertion.SetK(1) })
+	insertion.SetK(3)
+	assert.Equal(t, 3, insertion.K())
+}
+
+func TestBestInsertion_RequiredSkillsMustBeRespected(t *testing.T) {
+	t1 := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 10).Build()
+	v1 := vehicle.NewVehicleBuilder("v1").SetType(t1).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	v2 := vehicle.NewVehicleBuilder("v2").SetType(t1).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		AddSkill("drill").Build()
+	s1 := job.NewServiceBuilder[*job.Service]("s1").SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+		AddRequiredSkill("drill").Build()
+	p := newProblem(vrp.Finite, []problem.Vehicle{v1, v2}, s1)
+
+	routes := []*route.VehicleRoute{}
+	badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s1})
+
+	assert.Empty(t, badJobs)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "v2", routes[0].Vehicle().Id())
+}
+
+type forbiddenPosition struct {
+	forbidden problem.Job
+}
+
+func (c *forbiddenPosition) Fulfilled(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) constraint.ConstraintsStatus {
+	if jobAct, ok := prevAct.(problem.JobActivity); ok && jobAct.Job() == c.forbidden {
+		return constraint.NotFulfilled
+	}
+	return constraint.Fulfilled
+}
+
+type penalizedVehicle struct {
+	vehicleId string
+}
+
+func (c *penalizedVehicle) Costs(iContext *constraint.JobInsertionContext) float64 {
+	if iContext.NewVehicle().Id() == c.vehicleId {
+		return 1000.
+	}
+	return 0.
+}
+
+func TestBestInsertion_CustomConstraintsMustBeConsulted(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	v1, v2 := newOpenVehicle("v1", 10), newOpenVehicle("v2", 10)
+	p := newProblem(vrp.Finite, []problem.Vehicle{v1, v2}, s1, s2)
+	r := route.NewVehicleRouteBuilder(v1, route.EmptyRoute().Driver()).AddService(s1).Build()
+
+	insertion := newBestInsertion(p)
+	insertion.ConstraintManager().AddConstraint(&forbiddenPosition{forbidden: s1})
+	routes := []*route.VehicleRoute{r}
+	insertion.InsertJobs(&routes, []problem.Job{s2})
+	assert.Equal(t, []string{"s2", "s1"}, jobIds(r))
+
+	// with a penalty on v1 opening a new route with v2 is cheaper
+	insertion = newBestInsertion(p)
+	insertion.ConstraintManager().AddConstraint(&penalizedVehicle{vehicleId: "v1"})
+	r = route.NewVehicleRouteBuilder(v1, route.EmptyRoute().Driver()).AddService(s1).Build()
+	routes = []*route.VehicleRoute{r}
+	insertion.InsertJobs(&routes, []problem.Job{s2})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, []string{"s2"}, jobIds(routes[1]))
+}
+
+// newTypedVehicle creates a vehicle of its own type returning to the depot.
+func newTypedVehicle(id string, capacity int, fixedCosts, earliestStart, latestArrival float64) *vehicle.Vehicle {
+	t := vehicle.NewVehicleTypeBuilder(id+"Type").AddCapacityDimension(0, capacity).SetFixedCost(fixedCosts).Build()
+	return vehicle.NewVehicleBuilder(id).SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		SetEarliestStart(earliestStart).SetLatestArrival(latestArrival).Build()
+}
+
+func TestVehicleFleetManager_ShouldOfferOneAvailableVehiclePerTypeKey(t *testing.T) {
+	v1, v2, bike := newVehicle("v1", 1), newVehicle("v2", 1), newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64)
+	manager := NewVehicleFleetManager(newProblem(vrp.Finite, []problem.Vehicle{v1, v2, bike}))
+
+	assert.Equal(t, []problem.Vehicle{v1, bike}, manager.AvailableVehicles())
+
+	manager.InformInsertionStarts([]*route.VehicleRoute{route.NewVehicleRouteBuilder(v1, driver.NewNoDriver()).Build()}, nil)
+	assert.True(t, manager.IsLocked(v1))
+	assert.Equal(t, []problem.Vehicle{v2, bike}, manager.AvailableVehicles())
+
+	manager.InformVehicleSwitched(nil, v1, bike)
+	assert.False(t, manager.IsLocked(v1))
+	assert.Equal(t, []problem.Vehicle{v1}, manager.AvailableVehicles())
+
+	manager.UnlockAll()
+	assert.Len(t, manager.AvailableVehicles(), 2)
+}
+
+func TestVehicleFleetManager_WithInfiniteFleet_VehiclesShouldStayAvailable(t *testing.T) {
+	v := newVehicle("v", 1)
+	manager := NewVehicleFleetManager(newProblem(vrp.Infinite, []problem.Vehicle{v}))
+
+	manager.Lock(v)
+
+	assert.True(t, manager.IsLocked(v))
+	assert.True(t, manager.IsAvailable(v))
+	assert.Equal(t, []problem.Vehicle{v}, manager.AvailableVehicles())
+}
+
+func TestInsertion_ShouldSwitchToLargerVehicle(t *testing.T) {
+	for _, newInsertion := range []func(*vrp.VehicleRoutingProblem) InsertionStrategy{
+		func(p *vrp.VehicleRoutingProblem) InsertionStrategy { return newBestInsertion(p) },
+		func(p *vrp.VehicleRoutingProblem) InsertionStrategy { return newRegretInsertion(p) },
+	} {
+		s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+		bike, van := newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64), newTypedVehicle("van", 3, 50., 0., math.MaxFloat64)
+		p := newProblem(vrp.Finite, []problem.Vehicle{bike, van}, s1, s2)
+		insertion := newInsertion(p)
+		l := &recordingInsertionListener{}
+		insertion.AddListener(l)
+		routes := []*route.VehicleRoute{route.NewVehicleRouteBuilder(bike, driver.NewNoDriver()).AddService(s1).Build()}
+
+		// switching costs 40 of fixed costs and 20 of transport costs, a new route 50 + 40
+		badJobs := insertion.InsertJobs(&routes, []problem.Job{s2})
+
+		assert.Empty(t, badJobs)
+		assert.Len(t, routes, 1)
+		assert.Same(t, van, routes[0].Vehicle())
+		assert.ElementsMatch(t, []string{"s1", "s2"}, jobIds(routes[0]))
+		assert.Equal(t, []string{"bike->van"}, l.switched)
+	}
+}
+
+func TestBestInsertion_SwitchedVehicleMustServeWholeRoute(t *testing.T) {
+	tw, _ := activity.NewTimeWindow(0., 12.)
+	s2 := newService("s2", 20, 0)
+	for _, c := range []struct {
+		s1      *job.Service
+		van     *vehicle.Vehicle
+		badJobs []problem.Job
+	}{
+		// the van leaves too late for s1, but can serve s2 on its own
+		{job.NewServiceBuilder[*job.Service]("s1").SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+			AddSizeDimension(0, 1).AddTimeWindow(tw).Build(),
+			newTypedVehicle("van", 3, 50., 15., math.MaxFloat64), []problem.Job{}},
+		// the van can serve s1, but is late if it serves s2 as well
+		{newService("s1", 10, 0), newTypedVehicle("van", 3, 50., 0., 35.), []problem.Job{s2}},
+	} {
+		bike := newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64)
+		p := newProblem(vrp.Finite, []problem.Vehicle{bike, c.van}, c.s1, s2)
+		routes := []*route.VehicleRoute{route.NewVehicleRouteBuilder(bike, driver.NewNoDriver()).AddService(c.s1).Build()}
+
+		badJobs := newBestInsertion(p).InsertJobs(&routes, []problem.Job{s2})
+
+		assert.Equal(t, c.badJobs, badJobs)
+		assert.Same(t, bike, routes[0].Vehicle())
+		assert.Equal(t, []string{"s1"}, jobIds(routes[0]))
+	}
+}
+
+func TestBestInsertion_VehicleSwitchCanBeDisabled(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	bike, van := newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64), newTypedVehicle("van", 3, 50., 0., math.MaxFloat64)
+	p := newProblem(vrp.Finite, []problem.Vehicle{bike, van}, s1, s2)
+	insertion := newBestInsertion(p)
+	insertion.SetVehicleSwitchAllowed(false)
+	routes := []*route.VehicleRoute{route.NewVehicleRouteBuilder(bike, driver.NewNoDriver()).AddService(s1).Build()}
+
+	insertion.InsertJobs(&routes, []problem.Job{s2})
+
+	assert.Len(t, routes, 2)
+	assert.Same(t, bike, routes[0].Vehicle())
+}
+
+// forbiddenVehicle is a hard route constraint keeping a job from the routes of a vehicle.
+type forbiddenVehicle struct {
+	job       problem.Job
+	vehicleId string
+}
+
+func (c *forbiddenVehicle) Fulfilled(iContext *constraint.JobInsertionContext) bool {
+	return iContext.Job() != c.job || iContext.NewVehicle().Id() != c.vehicleId
+}
+
+// forbiddenActivityVehicle is a hard activity constraint keeping the activities of a job from a vehicle.
+type forbiddenActivityVehicle struct {
+	job       problem.Job
+	vehicleId string
+}
+
+func (c *forbiddenActivityVehicle) Fulfilled(iContext *constraint.JobInsertionContext, prevAct, newAct, nextAct problem.TourActivity,
+	prevActDepTime float64) constraint.ConstraintsStatus {
+	if jobAct, ok := newAct.(problem.JobActivity); ok && jobAct.Job() == c.job && iContext.NewVehicle().Id() == c.vehicleId {
+		return constraint.NotFulfilled
+	}
+	return constraint.Fulfilled
+}
+
+func TestBestInsertion_SwitchedVehicleMustFulfilHardConstraints(t *testing.T) {
+	s1, s2 := newService("s1", 10, 0), newService("s2", 20, 0)
+	for _, c := range []any{&forbiddenVehicle{job: s1, vehicleId: "van"}, &forbiddenActivityVehicle{job: s1, vehicleId: "van"}} {
+		bike, van := newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64), newTypedVehicle("van", 3, 50., 0., math.MaxFloat64)
+		p := newProblem(vrp.Finite, []problem.Vehicle{bike, van}, s1, s2)
+		insertion := newBestInsertion(p)
+		insertion.ConstraintManager().AddConstraint(c)
+		routes := []*route.VehicleRoute{route.NewVehicleRouteBuilder(bike, driver.NewNoDriver()).AddService(s1).Build()}
+
+		// without the constraint the van would take over the route of the bike
+		insertion.InsertJobs(&routes, []problem.Job{s2})
+
+		assert.Len(t, routes, 2)
+		assert.Same(t, bike, routes[0].Vehicle())
+		assert.Equal(t, []string{"s1"}, jobIds(routes[0]))
+		assert.Equal(t, []string{"s2"}, jobIds(routes[1]))
+	}
+}
+
+// routeConstraintCounter is a hard route constraint counting its evaluations of a job with a vehicle.
+type routeConstraintCounter struct {
+	job       problem.Job
+	vehicleId string
+	n         int
+}
+
+func (c *routeConstraintCounter) Fulfilled(iContext *constraint.JobInsertionContext) bool {
+	if iContext.Job() == c.job && iContext.NewVehicle().Id() == c.vehicleId {
+		c.n++
+	}
+	return true
+}
+
+func TestInsertion_VehicleSwitchShouldBeEvaluatedOncePerRouteChange(t *testing.T) {
+	s1, s2, s3 := newService("s1", 10, 0), newService("s2", 20, 0), newService("s3", 30, 0)
+	bike, van := newTypedVehicle("bike", 1, 10., 0., math.MaxFloat64), newTypedVehicle("van", 3, 50., 0., math.MaxFloat64)
+	p := newProblem(vrp.Finite, []problem.Vehicle{bike, van}, s1, s2, s3)
+	insertion := newBestInsertion(p)
+	counter := &routeConstraintCounter{job: s1, vehicleId: "van"}
+	insertion.ConstraintManager().AddConstraint(counter)
+	r := route.NewVehicleRouteBuilder(bike, driver.NewNoDriver()).AddService(s1).Build()
+	insertion.insertionListeners.InformInsertionStarts([]*route.VehicleRoute{r}, []problem.Job{s2, s3})
+
+	insertion.routeInsertion(s2, r, math.MaxFloat64)
+	insertion.routeInsertion(s3, r, math.MaxFloat64)
+	assert.Equal(t, 1, counter.n)
+
+	insertion.insertionListeners.InformJobInserted(s2, r, 0.)
+	insertion.routeInsertion(s3, r, math.MaxFloat64)
+	assert.Equal(t, 2, counter.n)
+
+	insertion.insertionListeners.InformInsertionStarts([]*route.VehicleRoute{r}, []problem.Job{s3})
+	insertion.routeInsertion(s3, r, math.MaxFloat64)
+	assert.Equal(t, 3, counter.n)
+}
@@ -0,0 +1,71 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+// JobInsertionCostsCalculator calculates the cheapest insertion of a job into a route.
+type JobInsertionCostsCalculator interface {
+	// InsertionData evaluates the insertion of jobToInsert into currentRoute served by newVehicle and newDriver,
+	// departing at newVehicleDepartureTime. It returns NewNoInsertionFound() if the job cannot be inserted
+	// or if every insertion costs at least bestKnownCosts.
+	InsertionData(currentRoute *route.VehicleRoute, jobToInsert problem.Job, newVehicle problem.Vehicle,
+		newVehicleDepartureTime float64, newDriver problem.Driver, bestKnownCosts float64) *InsertionData
+}
+
+var _ JobInsertionCostsCalculator = (*JobCalculatorSwitcher)(nil)
+
+// JobCalculatorSwitcher delegates to the calculator responsible for the type of the job to be inserted.
+type JobCalculatorSwitcher struct {
+	serviceCalculator  JobInsertionCostsCalculator
+	shipmentCalculator JobInsertionCostsCalculator
+}
+
+func NewJobCalculatorSwitcher(serviceCalculator, shipmentCalculator JobInsertionCostsCalculator) *JobCalculatorSwitcher {
+	return &JobCalculatorSwitcher{
+		serviceCalculator:  serviceCalculator,
+		shipmentCalculator: shipmentCalculator,
+	}
+}
+
+func (s *JobCalculatorSwitcher) InsertionData(currentRoute *route.VehicleRoute, jobToInsert problem.Job, newVehicle problem.Vehicle,
+	newVehicleDepartureTime float64, newDriver problem.Driver, bestKnownCosts float64) *InsertionData {
+	if jobToInsert.JobType().IsBreak() {
+		return NewNoInsertionFound()
+	}
+	if jobToInsert.JobType().IsShipment() {
+		return s.shipmentCalculator.InsertionData(currentRoute, jobToInsert, newVehicle, newVehicleDepartureTime, newDriver, bestKnownCosts)
+	}
+	return s.serviceCalculator.InsertionData(currentRoute, jobToInsert, newVehicle, newVehicleDepartureTime, newDriver, bestKnownCosts)
+}
+
+// newStartAndEnd creates the start and end of a route served by newVehicle departing at departureTime. They
+// are the first and last activities an insertion is evaluated against.
+func newStartAndEnd(newVehicle problem.Vehicle, departureTime float64) (*activity.Start, *activity.End) {
+	start := activity.NewStart(newVehicle.StartLocation(), newVehicle.EarliestDeparture(), math.MaxFloat64)
+	start.SetEndTime(departureTime)
+	end := activity.NewEnd(newVehicle.EndLocation(), 0., newVehicle.LatestArrival())
+	return start, end
+}
+
+// activityAt returns the activity succeeding insertion position, i.e. end if position is the last one.
+func activityAt(acts []problem.TourActivity, end problem.TourActivity, position int) problem.TourActivity {
+	if position < len(acts) {
+		return acts[position]
+	}
+	return end
+}
+
+// activityTimes returns the arrival and end time at act when the new vehicle of iContext leaves prevAct at prevActDepTime.
+func activityTimes(vrp *vrp.VehicleRoutingProblem, iContext *constraint.JobInsertionContext, prevAct, act problem.TourActivity,
+	prevActDepTime float64) (float64, float64) {
+	v, d := iContext.NewVehicle(), iContext.NewDriver()
+	arrTime := prevActDepTime + vrp.TransportCosts().TransportTime(prevAct.Location(), act.Location(), prevActDepTime, d, v)
+	endTime := math.Max(arrTime, act.TheoreticalEarliestOperationStartTime()) + vrp.ActivityCosts().ActivityDuration(act, arrTime, d, v)
+	return arrTime, endTime
+}
@@ -0,0 +1,152 @@
+package recreate
+
+import (
+	"fmt"
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/state"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"math"
+	"sort"
+)
+
+var _ InsertionStrategy = (*RegretInsertion)(nil)
+
+// RegretInsertion implements the regret-k insertion of Ropke and Pisinger (2006). In every step it inserts
+// the job that would lose most if it was not inserted now, i.e. the job with the largest gap between its
+// best insertion and its k-th best insertion, each in a different route. Jobs that can be inserted into
+// fewer than k routes are inserted first. Ties are broken by the cheaper best insertion.
+//
+// Opening a new route counts as a different route for every vehicle available to open it.
+type RegretInsertion struct {
+	AbstractInsertionStrategy
+	k int
+}
+
+// NewRegretInsertion creates a regret-2 insertion.
+func NewRegretInsertion(vrp *vrp.VehicleRoutingProblem, stateManager *state.StateManager, constraintManager *constraint.ConstraintManager) *RegretInsertion {
+	res := &RegretInsertion{k: 2}
+	res.AbstractInsertionStrategy = newAbstractInsertionStrategy(vrp, stateManager, constraintManager, res)
+	return res
+}
+
+func (s *RegretInsertion) SetK(k int) {
+	if k < 2 {
+		panic("k must be >= 2")
+	}
+	s.k = k
+}
+
+func (s *RegretInsertion) K() int {
+	return s.k
+}
+
+type scoredJob struct {
+	job       problem.Job
+	score     float64
+	insertion *InsertionData
+	route     *route.VehicleRoute
+}
+
+// isBetterThan reports whether j should be inserted before other.
+func (j *scoredJob) isBetterThan(other *scoredJob) bool {
+	if other == nil {
+		return true
+	}
+	if j.job.Priority() != other.job.Priority() {
+		return j.job.Priority() < other.job.Priority()
+	}
+	if j.score != other.score {
+		return j.score > other.score
+	}
+	return j.insertion.InsertionCost() < other.insertion.InsertionCost()
+}
+
+func (s *RegretInsertion) insertUnassignedJobs(vehicleRoutes *[]*route.VehicleRoute, unassignedJobs []problem.Job) []problem.Job {
+	badJobs := make([]problem.Job, 0)
+	jobsToInsert := s.shuffleAndSortByPriority(unassignedJobs)
+	// insertions into existing routes are cached and only re-evaluated for the route that changed
+	routeInsertions := make(map[problem.Job]map[*route.VehicleRoute]*InsertionData)
+	for _, job := range jobsToInsert {
+		routeInsertions[job] = make(map[*route.VehicleRoute]*InsertionData)
+		for _, vr := range *vehicleRoutes {
+			routeInsertions[job][vr] = s.routeInsertion(job, vr, math.MaxFloat64)
+		}
+	}
+
+	for len(jobsToInsert) > 0 {
+		var best *scoredJob
+		remaining := make([]problem.Job, 0, len(jobsToInsert))
+		for _, job := range jobsToInsert {
+			scored := s.score(job, routeInsertions[job], *vehicleRoutes)
+			if scored == nil {
+				badJobs = append(badJobs, job)
+				continue
+			}
+			remaining = append(remaining, job)
+			if scored.isBetterThan(best) {
+				best = scored
+			}
+		}
+		if best == nil {
+			break
+		}
+		s.insertJob(best.job, best.insertion, best.route, vehicleRoutes)
+
+		jobsToInsert = jobsToInsert[:0]
+		for _, job := range remaining {
+			if job == best.job {
+				continue
+			}
+			jobsToInsert = append(jobsToInsert, job)
+			for vr, data := range routeInsertions[job] {
+				// insertions switching to a vehicle that is no longer available are outdated as well
+				if vr == best.route || (!data.IsNoInsertionFound() && data.SelectedVehicle() != vr.Vehicle() &&
+					!s.fleetManager.IsAvailable(data.SelectedVehicle())) {
+					routeInsertions[job][vr] = s.routeInsertion(job, vr, math.MaxFloat64)
+				}
+			}
+			if _, ok := routeInsertions[job][best.route]; !ok {
+				routeInsertions[job][best.route] = s.routeInsertion(job, best.route, math.MaxFloat64)
+			}
+		}
+	}
+	return badJobs
+}
+
+// score computes the regret of job. It returns nil if the job cannot be inserted at all.
+func (s *RegretInsertion) score(job problem.Job, routeInsertions map[*route.VehicleRoute]*InsertionData, vehicleRoutes []*route.VehicleRoute) *scoredJob {
+	var best *scoredJob
+	costs := make([]float64, 0, len(vehicleRoutes)+1)
+	for _, vr := range vehicleRoutes {
+		data := routeInsertions[vr]
+		if data.IsNoInsertionFound() {
+			continue
+		}
+		costs = append(costs, data.InsertionCost())
+		if best == nil || data.InsertionCost() < best.insertion.InsertionCost() {
+			best = &scoredJob{job: job, insertion: data, route: vr}
+		}
+	}
+	for _, data := range s.newRouteInsertions(job, math.MaxFloat64) {
+		costs = append(costs, data.InsertionCost())
+		if best == nil || data.InsertionCost() < best.insertion.InsertionCost() {
+			best = &scoredJob{job: job, insertion: data, route: s.newRoute(data)}
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	if len(costs) < s.k {
+		best.score = math.MaxFloat64
+	} else {
+		sort.Float64s(costs)
+		best.score = costs[s.k-1] - costs[0]
+	}
+	return best
+}
+
+func (s *RegretInsertion) String() string {
+	return fmt.Sprintf("[name=regretInsertion][k=%d]", s.k)
+}
@@ -0,0 +1,95 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ JobInsertionCostsCalculator = (*ServiceInsertionCalculator)(nil)
+
+// ServiceInsertionCalculator evaluates every position of a route and every time window of a service and
+// returns the cheapest insertion fulfilling the hard constraints of the ConstraintManager.
+//
+// The insertion costs are the marginal costs calculated by the ActivityInsertionCostsCalculator, the costs of the
+// soft constraints and, if the route is empty, the fixed costs of the vehicle.
+type ServiceInsertionCalculator struct {
+	vrp                    *vrp.VehicleRoutingProblem
+	constraintManager      *constraint.ConstraintManager
+	activityInsertionCosts ActivityInsertionCostsCalculator
+}
+
+func NewServiceInsertionCalculator(vrp *vrp.VehicleRoutingProblem, constraintManager *constraint.ConstraintManager) *ServiceInsertionCalculator {
+	return &ServiceInsertionCalculator{
+		vrp:                    vrp,
+		constraintManager:      constraintManager,
+		activityInsertionCosts: NewLocalActivityInsertionCostsCalculator(vrp.TransportCosts(), vrp.ActivityCosts()),
+	}
+}
+
+func (c *ServiceInsertionCalculator) SetActivityInsertionCostsCalculator(activityInsertionCosts ActivityInsertionCostsCalculator) {
+	c.activityInsertionCosts = activityInsertionCosts
+}
+
+func (c *ServiceInsertionCalculator) InsertionData(currentRoute *route.VehicleRoute, jobToInsert problem.Job, newVehicle problem.Vehicle,
+	newVehicleDepartureTime float64, newDriver problem.Driver, bestKnownCosts float64) *InsertionData {
+	service, ok := jobToInsert.(problem.Service)
+	if !ok || service.Location() == nil {
+		return NewNoInsertionFound()
+	}
+	iContext := constraint.NewJobInsertionContext(currentRoute, jobToInsert, newVehicle, newDriver, newVehicleDepartureTime)
+	if !c.constraintManager.HardRouteConstraintsFulfilled(iContext) {
+		return NewNoInsertionFound()
+	}
+	additionalCosts := c.constraintManager.SoftRouteCosts(iContext)
+	if currentRoute.IsEmpty() {
+		additionalCosts += newVehicle.Type().VehicleCostParams().Fix()
+	}
+	if additionalCosts >= bestKnownCosts {
+		return NewNoInsertionFound()
+	}
+
+	newAct := c.vrp.JobActivityFactory()(jobToInsert)[0]
+	iContext.SetAssociatedActivities([]problem.TourActivity{newAct})
+	activityContext := constraint.NewActivityContext(0, 0., 0.)
+	iContext.SetActivityContext(activityContext)
+	start, end := newStartAndEnd(newVehicle, newVehicleDepartureTime)
+	acts := currentRoute.Activities()
+
+	bestCosts := bestKnownCosts
+	bestPosition := -1
+	var bestTimeWindow problem.TimeWindow
+	for _, tw := range service.TimeWindows() {
+		newAct.SetTheoreticalEarliestOperationStartTime(tw.Start())
+		newAct.SetTheoreticalLatestOperationStartTime(tw.End())
+		var prevAct problem.TourActivity = start
+		prevActDepTime := newVehicleDepartureTime
+		for position := 0; position <= len(acts); position++ {
+			nextAct := activityAt(acts, end, position)
+			activityContext.SetInsertionIndex(position)
+			status := c.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, newAct, nextAct, prevActDepTime)
+			if status == constraint.Fulfilled {
+				costs := additionalCosts +
+					c.activityInsertionCosts.Costs(iContext, prevAct, newAct, nextAct, prevActDepTime) +
+					c.constraintManager.SoftActivityCosts(iContext, prevAct, newAct, nextAct, prevActDepTime)
+				if costs < bestCosts {
+					bestCosts = costs
+					bestPosition = position
+					bestTimeWindow = tw
+				}
+			} else if status == constraint.NotFulfilledBreak {
+				break
+			}
+			_, prevActDepTime = activityTimes(c.vrp, iContext, prevAct, nextAct, prevActDepTime)
+			prevAct = nextAct
+		}
+	}
+	if bestPosition < 0 {
+		return NewNoInsertionFound()
+	}
+	data := NewInsertionData(bestCosts, -1, bestPosition, newVehicle, newDriver)
+	data.SetVehicleDepartureTime(newVehicleDepartureTime)
+	data.SetTimeWindows([]problem.TimeWindow{bestTimeWindow})
+	return data
+}
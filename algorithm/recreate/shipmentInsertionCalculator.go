@@ -0,0 +1,146 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ JobInsertionCostsCalculator = (*ShipmentInsertionCalculator)(nil)
+
+// ShipmentInsertionCalculator evaluates every pair of pickup and delivery positions, with the pickup
+// preceding the delivery, and returns the cheapest insertion fulfilling the hard constraints of the
+// ConstraintManager. The activities between pickup and delivery are shifted in time, hence the walk along
+// the route stops as soon as one of them cannot start within its time window anymore.
+type ShipmentInsertionCalculator struct {
+	vrp                    *vrp.VehicleRoutingProblem
+	constraintManager      *constraint.ConstraintManager
+	activityInsertionCosts ActivityInsertionCostsCalculator
+}
+
+func NewShipmentInsertionCalculator(vrp *vrp.VehicleRoutingProblem, constraintManager *constraint.ConstraintManager) *ShipmentInsertionCalculator {
+	return &ShipmentInsertionCalculator{
+		vrp:                    vrp,
+		constraintManager:      constraintManager,
+		activityInsertionCosts: NewLocalActivityInsertionCostsCalculator(vrp.TransportCosts(), vrp.ActivityCosts()),
+	}
+}
+
+func (c *ShipmentInsertionCalculator) SetActivityInsertionCostsCalculator(activityInsertionCosts ActivityInsertionCostsCalculator) {
+	c.activityInsertionCosts = activityInsertionCosts
+}
+
+func (c *ShipmentInsertionCalculator) InsertionData(currentRoute *route.VehicleRoute, jobToInsert problem.Job, newVehicle problem.Vehicle,
+	newVehicleDepartureTime float64, newDriver problem.Driver, bestKnownCosts float64) *InsertionData {
+	shipment, ok := jobToInsert.(problem.Shipment)
+	if !ok {
+		return NewNoInsertionFound()
+	}
+	iContext := constraint.NewJobInsertionContext(currentRoute, jobToInsert, newVehicle, newDriver, newVehicleDepartureTime)
+	if !c.constraintManager.HardRouteConstraintsFulfilled(iContext) {
+		return NewNoInsertionFound()
+	}
+	additionalCosts := c.constraintManager.SoftRouteCosts(iContext)
+	if currentRoute.IsEmpty() {
+		additionalCosts += newVehicle.Type().VehicleCostParams().Fix()
+	}
+	if additionalCosts >= bestKnownCosts {
+		return NewNoInsertionFound()
+	}
+
+	acts := c.vrp.JobActivityFactory()(jobToInsert)
+	pickup, delivery := acts[0], acts[1]
+	iContext.SetAssociatedActivities([]problem.TourActivity{pickup, delivery})
+	start, end := newStartAndEnd(newVehicle, newVehicleDepartureTime)
+
+	best := &shipmentInsertion{costs: bestKnownCosts, pickupPosition: -1}
+	for _, pickupTw := range shipment.Activities()[0].TimeWindows() {
+		for _, deliveryTw := range shipment.Activities()[1].TimeWindows() {
+			pickup.SetTheoreticalEarliestOperationStartTime(pickupTw.Start())
+			pickup.SetTheoreticalLatestOperationStartTime(pickupTw.End())
+			delivery.SetTheoreticalEarliestOperationStartTime(deliveryTw.Start())
+			delivery.SetTheoreticalLatestOperationStartTime(deliveryTw.End())
+			c.evaluate(iContext, currentRoute.Activities(), start, end, pickup, delivery, pickupTw, deliveryTw, additionalCosts, best)
+		}
+	}
+	if best.pickupPosition < 0 {
+		return NewNoInsertionFound()
+	}
+	data := NewInsertionData(best.costs, best.pickupPosition, best.deliveryPosition, newVehicle, newDriver)
+	data.SetVehicleDepartureTime(newVehicleDepartureTime)
+	data.SetTimeWindows([]problem.TimeWindow{best.pickupTw, best.deliveryTw})
+	return data
+}
+
+type shipmentInsertion struct {
+	costs            float64
+	pickupPosition   int
+	deliveryPosition int
+	pickupTw         problem.TimeWindow
+	deliveryTw       problem.TimeWindow
+}
+
+// evaluate walks along acts and evaluates every pickup position and, for each of them, every delivery position.
+func (c *ShipmentInsertionCalculator) evaluate(iContext *constraint.JobInsertionContext, acts []problem.TourActivity, start, end problem.TourActivity,
+	pickup, delivery problem.TourActivity, pickupTw, deliveryTw problem.TimeWindow, additionalCosts float64, best *shipmentInsertion) {
+	activityContext := constraint.NewActivityContext(0, 0., 0.)
+	iContext.SetActivityContext(activityContext)
+	prevAct, prevActDepTime := start, iContext.NewDepTime()
+	for pickupPosition := 0; pickupPosition <= len(acts); pickupPosition++ {
+		nextAct := activityAt(acts, end, pickupPosition)
+		activityContext.SetInsertionIndex(pickupPosition)
+		iContext.SetRelatedActivityContext(nil)
+		status := c.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, pickup, nextAct, prevActDepTime)
+		if status == constraint.NotFulfilledBreak {
+			break
+		}
+		if status == constraint.Fulfilled {
+			pickupCosts := additionalCosts +
+				c.activityInsertionCosts.Costs(iContext, prevAct, pickup, nextAct, prevActDepTime) +
+				c.constraintManager.SoftActivityCosts(iContext, prevAct, pickup, nextAct, prevActDepTime)
+			pickupArrTime, pickupEndTime := activityTimes(c.vrp, iContext, prevAct, pickup, prevActDepTime)
+			iContext.SetRelatedActivityContext(constraint.NewActivityContext(pickupPosition, pickupArrTime, pickupEndTime))
+			c.evaluateDeliveryPositions(iContext, activityContext, acts, end, pickup, delivery, pickupEndTime, pickupPosition,
+				pickupCosts, pickupTw, deliveryTw, best)
+		}
+		_, prevActDepTime = activityTimes(c.vrp, iContext, prevAct, nextAct, prevActDepTime)
+		prevAct = nextAct
+	}
+}
+
+// evaluateDeliveryPositions evaluates the delivery positions following the pickup inserted at pickupPosition.
+// Activities passed with the shipment loaded are shifted in time, the walk stops if one of them gets late.
+func (c *ShipmentInsertionCalculator) evaluateDeliveryPositions(iContext *constraint.JobInsertionContext, activityContext *constraint.ActivityContext,
+	acts []problem.TourActivity, end, pickup, delivery problem.TourActivity, pickupEndTime float64, pickupPosition int,
+	pickupCosts float64, pickupTw, deliveryTw problem.TimeWindow, best *shipmentInsertion) {
+	prevAct, prevActDepTime := pickup, pickupEndTime
+	for deliveryPosition := pickupPosition; deliveryPosition <= len(acts); deliveryPosition++ {
+		nextAct := activityAt(acts, end, deliveryPosition)
+		activityContext.SetInsertionIndex(deliveryPosition)
+		status := c.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, delivery, nextAct, prevActDepTime)
+		if status == constraint.NotFulfilledBreak {
+			break
+		}
+		if status == constraint.Fulfilled {
+			costs := pickupCosts +
+				c.activityInsertionCosts.Costs(iContext, prevAct, delivery, nextAct, prevActDepTime) +
+				c.constraintManager.SoftActivityCosts(iContext, prevAct, delivery, nextAct, prevActDepTime)
+			if costs < best.costs {
+				best.costs = costs
+				best.pickupPosition = pickupPosition
+				best.deliveryPosition = deliveryPosition
+				best.pickupTw = pickupTw
+				best.deliveryTw = deliveryTw
+			}
+		}
+		if deliveryPosition == len(acts) {
+			break
+		}
+		arrTime, endTime := activityTimes(c.vrp, iContext, prevAct, nextAct, prevActDepTime)
+		if arrTime > nextAct.TheoreticalLatestOperationStartTime() {
+			break
+		}
+		prevAct, prevActDepTime = nextAct, endTime
+	}
+}
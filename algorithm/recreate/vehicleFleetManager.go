@@ -0,0 +1,99 @@
+package recreate
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"sort"
+)
+
+var (
+	_ InsertionStartsListener = (*VehicleFleetManager)(nil)
+	_ JobInsertedListener     = (*VehicleFleetManager)(nil)
+	_ VehicleSwitchedListener = (*VehicleFleetManager)(nil)
+)
+
+// VehicleFleetManager keeps track of the vehicles in use, i.e. serving a route, and of the vehicles available to
+// open a new route or to replace the vehicle of a route. A vehicle in use is locked. With a finite fleet a locked
+// vehicle is not available, with an infinite fleet every vehicle is always available.
+//
+// Registered as insertion listener, it locks the vehicles of the routes whenever an insertion starts and follows
+// the new routes and vehicle switches of the insertion.
+type VehicleFleetManager struct {
+	vehicles  []problem.Vehicle
+	fleetSize vrp.FleetSize
+	locked    map[problem.Vehicle]bool
+}
+
+func NewVehicleFleetManager(vrp *vrp.VehicleRoutingProblem) *VehicleFleetManager {
+	vehicles := vrp.Vehicles()
+	sort.Slice(vehicles, func(i, j int) bool {
+		return vehicles[i].Index() < vehicles[j].Index()
+	})
+	return &VehicleFleetManager{
+		vehicles:  vehicles,
+		fleetSize: vrp.FleetSize(),
+		locked:    make(map[problem.Vehicle]bool),
+	}
+}
+
+// Lock marks v as in use.
+func (m *VehicleFleetManager) Lock(v problem.Vehicle) {
+	m.locked[v] = true
+}
+
+// Unlock marks v as no longer in use.
+func (m *VehicleFleetManager) Unlock(v problem.Vehicle) {
+	delete(m.locked, v)
+}
+
+func (m *VehicleFleetManager) UnlockAll() {
+	clear(m.locked)
+}
+
+func (m *VehicleFleetManager) IsLocked(v problem.Vehicle) bool {
+	return m.locked[v]
+}
+
+// IsAvailable reports whether v may open a new route or replace the vehicle of a route.
+func (m *VehicleFleetManager) IsAvailable(v problem.Vehicle) bool {
+	return m.fleetSize == vrp.Infinite || !m.locked[v]
+}
+
+// Vehicles returns all vehicles of the fleet ordered by index.
+func (m *VehicleFleetManager) Vehicles() []problem.Vehicle {
+	return m.vehicles
+}
+
+// AvailableVehicles returns the first available vehicle of every vehicle type key, ordered by index. Vehicles
+// sharing a key are interchangeable, thus one of them suffices to evaluate an insertion.
+func (m *VehicleFleetManager) AvailableVehicles() []problem.Vehicle {
+	available := make([]problem.Vehicle, 0)
+	typeKeys := make(map[int]bool)
+	for _, v := range m.vehicles {
+		if !m.IsAvailable(v) || typeKeys[v.VehicleTypeIdentifier().Index()] {
+			continue
+		}
+		typeKeys[v.VehicleTypeIdentifier().Index()] = true
+		available = append(available, v)
+	}
+	return available
+}
+
+// InformInsertionStarts locks exactly the vehicles of vehicleRoutes.
+func (m *VehicleFleetManager) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	m.UnlockAll()
+	for _, vr := range vehicleRoutes {
+		m.Lock(vr.Vehicle())
+	}
+}
+
+// InformJobInserted locks the vehicle of inRoute, which might be a new route.
+func (m *VehicleFleetManager) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	m.Lock(inRoute.Vehicle())
+}
+
+func (m *VehicleFleetManager) InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle) {
+	m.Unlock(oldVehicle)
+	m.Lock(newVehicle)
+}
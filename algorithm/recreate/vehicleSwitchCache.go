@@ -0,0 +1,55 @@
+package recreate
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+)
+
+var (
+	_ InsertionStartsListener = (*vehicleSwitchCache)(nil)
+	_ JobInsertedListener     = (*vehicleSwitchCache)(nil)
+	_ VehicleSwitchedListener = (*vehicleSwitchCache)(nil)
+)
+
+// vehicleSwitch is the result of evaluating whether a vehicle may take over a route.
+type vehicleSwitch struct {
+	costs    float64
+	feasible bool
+}
+
+// vehicleSwitchCache caches the vehicle switches of the routes per vehicle type key, since they do not depend on
+// the job to insert. Registered as insertion listener, it forgets all routes when an insertion starts and a route
+// when a job has been inserted into it or its vehicle has been switched.
+type vehicleSwitchCache struct {
+	switches map[*route.VehicleRoute]map[int]vehicleSwitch
+}
+
+func newVehicleSwitchCache() *vehicleSwitchCache {
+	return &vehicleSwitchCache{
+		switches: make(map[*route.VehicleRoute]map[int]vehicleSwitch),
+	}
+}
+
+func (c *vehicleSwitchCache) get(vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle) (vehicleSwitch, bool) {
+	sw, ok := c.switches[vehicleRoute][newVehicle.VehicleTypeIdentifier().Index()]
+	return sw, ok
+}
+
+func (c *vehicleSwitchCache) put(vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle, sw vehicleSwitch) {
+	if _, ok := c.switches[vehicleRoute]; !ok {
+		c.switches[vehicleRoute] = make(map[int]vehicleSwitch)
+	}
+	c.switches[vehicleRoute][newVehicle.VehicleTypeIdentifier().Index()] = sw
+}
+
+func (c *vehicleSwitchCache) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	clear(c.switches)
+}
+
+func (c *vehicleSwitchCache) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	delete(c.switches, inRoute)
+}
+
+func (c *vehicleSwitchCache) InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle) {
+	delete(c.switches, vehicleRoute)
+}
@@ -0,0 +1,175 @@
+package recreate
+
+import (
+	"gsprit/algorithm/constraint"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"math"
+)
+
+// switchVehicles returns the available vehicles that may replace the vehicle of vehicleRoute. Vehicles sharing
+// the vehicle type key of the route's vehicle are left out, since they neither change the costs nor the
+// feasibility of the route. Breaks are tied to their vehicle, thus routes with a break keep their vehicle and
+// vehicles with a break only open new routes.
+func (s *AbstractInsertionStrategy) switchVehicles(vehicleRoute *route.VehicleRoute) []problem.Vehicle {
+	if vehicleRoute.IsEmpty() {
+		return nil
+	}
+	for _, act := range vehicleRoute.Activities() {
+		if _, ok := act.(*activity.BreakActivity); ok {
+			return nil
+		}
+	}
+	current := vehicleRoute.Vehicle()
+	vehicles := make([]problem.Vehicle, 0)
+	for _, v := range s.fleetManager.AvailableVehicles() {
+		if v.Break() != nil || v.VehicleTypeIdentifier().Equals(current.VehicleTypeIdentifier()) {
+			continue
+		}
+		vehicles = append(vehicles, v)
+	}
+	return vehicles
+}
+
+// vehicleSwitchInsertion evaluates the insertion of job into vehicleRoute with its vehicle replaced by
+// newVehicle. Its costs are the costs of the insertion with newVehicle plus the costs of switching the vehicle,
+// i.e. the difference in the fixed and variable costs of the route served by newVehicle instead of its vehicle.
+func (s *AbstractInsertionStrategy) vehicleSwitchInsertion(job problem.Job, vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle,
+	bestKnownCosts float64) *InsertionData {
+	switchCosts, ok := s.vehicleSwitch(vehicleRoute, newVehicle)
+	if !ok || switchCosts >= bestKnownCosts {
+		return NewNoInsertionFound()
+	}
+	departureTime := newVehicle.EarliestDeparture()
+	data := s.jobCalculator.InsertionData(vehicleRoute, job, newVehicle, departureTime, vehicleRoute.Driver(), bestKnownCosts-switchCosts)
+	if data.IsNoInsertionFound() || !s.servesRoute(vehicleRoute, job, data) {
+		return NewNoInsertionFound()
+	}
+	res := NewInsertionData(data.InsertionCost()+switchCosts, data.PickupInsertionIndex(), data.DeliveryInsertionIndex(),
+		newVehicle, vehicleRoute.Driver())
+	res.SetVehicleDepartureTime(departureTime)
+	res.SetTimeWindows(data.TimeWindows())
+	return res
+}
+
+// vehicleSwitch returns the costs of serving vehicleRoute with newVehicle instead of its vehicle and whether
+// newVehicle may serve it. Both do not depend on the job to insert, thus they are cached until the route changes.
+func (s *AbstractInsertionStrategy) vehicleSwitch(vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle) (float64, bool) {
+	if sw, ok := s.switchCache.get(vehicleRoute, newVehicle); ok {
+		return sw.costs, sw.feasible
+	}
+	costs, ok := s.vehicleSwitchCosts(vehicleRoute, newVehicle)
+	ok = ok && s.fulfilsConstraints(vehicleRoute, newVehicle)
+	s.switchCache.put(vehicleRoute, newVehicle, vehicleSwitch{costs: costs, feasible: ok})
+	return costs, ok
+}
+
+// vehicleSwitchCosts returns the costs of serving vehicleRoute with newVehicle instead of its vehicle and whether
+// newVehicle has the capacity required by the route and serves it in time.
+func (s *AbstractInsertionStrategy) vehicleSwitchCosts(vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle) (float64, bool) {
+	if !s.stateManager.MaxLoad(vehicleRoute).IsLessOrEqual(newVehicle.Type().CapacityDimensions()) {
+		return 0., false
+	}
+	departureTime, _ := vehicleRoute.DepartureTime()
+	currentCosts, _ := s.routeCosts(vehicleRoute, vehicleRoute.Vehicle(), departureTime, vehicleRoute.Activities())
+	newCosts, ok := s.routeCosts(vehicleRoute, newVehicle, newVehicle.EarliestDeparture(), vehicleRoute.Activities())
+	return newCosts + newVehicle.Type().VehicleCostParams().Fix() -
+		currentCosts - vehicleRoute.Vehicle().Type().VehicleCostParams().Fix(), ok
+}
+
+// fulfilsConstraints checks whether newVehicle may serve the activities of vehicleRoute as far as the hard route
+// and activity constraints are concerned. It builds a route of newVehicle from copies of the activities, each
+// appended activity checked like an insertion at the end of the route, and removes its states afterwards.
+func (s *AbstractInsertionStrategy) fulfilsConstraints(vehicleRoute *route.VehicleRoute, newVehicle problem.Vehicle) bool {
+	d := vehicleRoute.Driver()
+	vr := route.NewVehicleRouteBuilder(newVehicle, d).SetDepartureTime(newVehicle.EarliestDeparture()).Build()
+	defer s.stateManager.RemoveRoute(vr)
+	s.stateManager.UpdateRoute(vr)
+	pickups := make(map[problem.Job]int)
+	for i, act := range vehicleRoute.Activities() {
+		jobAct, ok := act.Duplicate().(problem.JobActivity)
+		if !ok {
+			return false
+		}
+		iContext := constraint.NewJobInsertionContext(vr, jobAct.Job(), newVehicle, d, newVehicle.EarliestDeparture())
+		iContext.SetActivityContext(constraint.NewActivityContext(i, 0., 0.))
+		if _, ok := jobAct.(*activity.DeliverShipment); ok {
+			iContext.SetRelatedActivityContext(constraint.NewActivityContext(pickups[jobAct.Job()], 0., 0.))
+		} else if !s.constraintManager.HardRouteConstraintsFulfilled(iContext) {
+			// the route constraints are checked once per job, i.e. with the pickup of a shipment
+			return false
+		}
+		if _, ok := jobAct.(*activity.PickupShipment); ok {
+			pickups[jobAct.Job()] = i
+		}
+		var prevAct problem.TourActivity = vr.Start()
+		if i > 0 {
+			prevAct = vr.Activities()[i-1]
+		}
+		if s.constraintManager.HardActivityConstraintsFulfilled(iContext, prevAct, jobAct, vr.End(), prevAct.EndTime()) != constraint.Fulfilled {
+			return false
+		}
+		vr.TourActivities().AddActivityToEnd(jobAct)
+		if !newVehicle.IsReturnToDepot() {
+			vr.End().SetLocation(jobAct.Location())
+		}
+		s.stateManager.UpdateRoute(vr)
+	}
+	return true
+}
+
+// servesRoute checks whether the vehicle selected by data is in time at every activity of vehicleRoute once job
+// has been inserted. The insertion calculator only checks the activities of job itself against the latest
+// operation start times cached for the vehicle of the route.
+func (s *AbstractInsertionStrategy) servesRoute(vehicleRoute *route.VehicleRoute, job problem.Job, data *InsertionData) bool {
+	jobActs := s.vrp.JobActivityFactory()(job)
+	for i, tw := range data.TimeWindows() {
+		jobActs[i].SetTheoreticalEarliestOperationStartTime(tw.Start())
+		jobActs[i].SetTheoreticalLatestOperationStartTime(tw.End())
+	}
+	acts := append([]problem.TourActivity{}, vehicleRoute.Activities()...)
+	if job.JobType().IsShipment() {
+		acts = insertActivity(acts, data.DeliveryInsertionIndex(), jobActs[1])
+		acts = insertActivity(acts, data.PickupInsertionIndex(), jobActs[0])
+	} else {
+		acts = insertActivity(acts, data.DeliveryInsertionIndex(), jobActs[0])
+	}
+	_, ok := s.routeCosts(vehicleRoute, data.SelectedVehicle(), data.VehicleDepartureTime(), acts)
+	return ok
+}
+
+// routeCosts returns the transport and activity costs of v serving acts, departing at departureTime, and whether
+// v starts every activity within its time window and arrives in time at the end of the route.
+func (s *AbstractInsertionStrategy) routeCosts(vehicleRoute *route.VehicleRoute, v problem.Vehicle, departureTime float64,
+	acts []problem.TourActivity) (float64, bool) {
+	transportCosts, activityCosts := s.vrp.TransportCosts(), s.vrp.ActivityCosts()
+	d := vehicleRoute.Driver()
+	costs := 0.
+	feasible := true
+	prevLocation, prevEndTime := v.StartLocation(), departureTime
+	for _, act := range acts {
+		arrTime := prevEndTime + transportCosts.TransportTime(prevLocation, act.Location(), prevEndTime, d, v)
+		if arrTime > act.TheoreticalLatestOperationStartTime() {
+			feasible = false
+		}
+		costs += transportCosts.TransportCost(prevLocation, act.Location(), prevEndTime, d, v) +
+			activityCosts.ActivityCost(act, arrTime, d, v)
+		prevLocation = act.Location()
+		prevEndTime = math.Max(arrTime, act.TheoreticalEarliestOperationStartTime()) + activityCosts.ActivityDuration(act, arrTime, d, v)
+	}
+	// a vehicle not returning to its depot ends its route right after the last activity
+	if v.IsReturnToDepot() {
+		costs += transportCosts.TransportCost(prevLocation, v.EndLocation(), prevEndTime, d, v)
+		prevEndTime += transportCosts.TransportTime(prevLocation, v.EndLocation(), prevEndTime, d, v)
+	}
+	return costs, feasible && prevEndTime <= v.LatestArrival()
+}
+
+// insertActivity returns acts with act inserted at index.
+func insertActivity(acts []problem.TourActivity, index int, act problem.TourActivity) []problem.TourActivity {
+	acts = append(acts, nil)
+	copy(acts[index+1:], acts[index:])
+	acts[index] = act
+	return acts
+}
@@ -0,0 +1,177 @@
+package ruin
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"gsprit/util"
+	"math"
+	"math/rand/v2"
+)
+
+// RuinShareFactory determines how many jobs are removed in one ruin.
+type RuinShareFactory interface {
+	CreateNumberToBeRemoved() int
+}
+
+// RuinShareFactoryImpl draws the number of jobs to be removed uniformly from [minShare, maxShare].
+type RuinShareFactoryImpl struct {
+	minShare int
+	maxShare int
+	random   *rand.Rand
+}
+
+func NewRuinShareFactoryImpl(minShare, maxShare int) *RuinShareFactoryImpl {
+	if minShare < 0 || maxShare < minShare {
+		panic("minShare must not be negative and maxShare must not be smaller than minShare")
+	}
+	return &RuinShareFactoryImpl{
+		minShare: minShare,
+		maxShare: maxShare,
+		random:   util.NewRandom(util.DefaultSeed),
+	}
+}
+
+func (f *RuinShareFactoryImpl) SetRandom(r *rand.Rand) {
+	f.random = r
+}
+
+func (f *RuinShareFactoryImpl) CreateNumberToBeRemoved() int {
+	return f.minShare + f.random.IntN(f.maxShare-f.minShare+1)
+}
+
+func (f *RuinShareFactoryImpl) String() string {
+	return fmt.Sprintf("[minShare=%d][maxShare=%d]", f.minShare, f.maxShare)
+}
+
+type fractionRuinShareFactory struct {
+	nOfJobs  int
+	fraction float64
+}
+
+func (f *fractionRuinShareFactory) CreateNumberToBeRemoved() int {
+	return int(math.Ceil(float64(f.nOfJobs) * f.fraction))
+}
+
+func (f *fractionRuinShareFactory) String() string {
+	return fmt.Sprintf("[fraction=%.2f]", f.fraction)
+}
+
+// NewFractionRuinShareFactory removes the given fraction of all jobs of vrp.
+func NewFractionRuinShareFactory(vrp *vrp.VehicleRoutingProblem, fraction float64) RuinShareFactory {
+	if fraction < 0. || fraction > 1. {
+		panic("fraction of jobs to be ruined must be within [0,1]")
+	}
+	return &fractionRuinShareFactory{
+		nOfJobs:  len(vrp.Jobs()),
+		fraction: fraction,
+	}
+}
+
+type routesRuiner interface {
+	ruinRoutes(vehicleRoutes []*route.VehicleRoute) []problem.Job
+}
+
+// AbstractRuinStrategy implements what all ruin strategies have in common, i.e. listener handling,
+// the ruin share and the removal of a job from its route. The actual selection of jobs is delegated to spi.
+type AbstractRuinStrategy struct {
+	vrp              *vrp.VehicleRoutingProblem
+	jobs             map[string]problem.Job
+	ruinListeners    *RuinListeners
+	random           *rand.Rand
+	ruinShareFactory RuinShareFactory
+	spi              routesRuiner
+}
+
+func newAbstractRuinStrategy(vrp *vrp.VehicleRoutingProblem, spi routesRuiner) AbstractRuinStrategy {
+	return AbstractRuinStrategy{
+		vrp:           vrp,
+		jobs:          vrp.Jobs(),
+		ruinListeners: NewRuinListeners(),
+		random:        util.NewRandom(util.DefaultSeed),
+		spi:           spi,
+	}
+}
+
+func (s *AbstractRuinStrategy) Ruin(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	s.ruinListeners.RuinStarts(vehicleRoutes)
+	unassignedJobs := s.spi.ruinRoutes(vehicleRoutes)
+	s.ruinListeners.RuinEnds(vehicleRoutes, unassignedJobs)
+	return unassignedJobs
+}
+
+// SetRandom sets the random number generator of the strategy and of its ruin share factory.
+func (s *AbstractRuinStrategy) SetRandom(r *rand.Rand) {
+	s.random = r
+	if f, ok := s.ruinShareFactory.(interface{ SetRandom(r *rand.Rand) }); ok {
+		f.SetRandom(r)
+	}
+}
+
+func (s *AbstractRuinStrategy) SetRuinShareFactory(f RuinShareFactory) {
+	s.ruinShareFactory = f
+}
+
+func (s *AbstractRuinStrategy) RuinShareFactory() RuinShareFactory {
+	return s.ruinShareFactory
+}
+
+func (s *AbstractRuinStrategy) AddListener(l RuinListener) {
+	s.ruinListeners.AddListener(l)
+}
+
+func (s *AbstractRuinStrategy) RemoveListener(l RuinListener) {
+	s.ruinListeners.RemoveListener(l)
+}
+
+func (s *AbstractRuinStrategy) Listeners() []RuinListener {
+	return s.ruinListeners.Listeners()
+}
+
+func (s *AbstractRuinStrategy) numberToBeRemoved() int {
+	return s.ruinShareFactory.CreateNumberToBeRemoved()
+}
+
+// removeJob removes job from the route serving it and informs the listeners.
+func (s *AbstractRuinStrategy) removeJob(job problem.Job, vehicleRoutes []*route.VehicleRoute) bool {
+	for _, r := range vehicleRoutes {
+		if s.removeJobFromRoute(job, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AbstractRuinStrategy) removeJobFromRoute(job problem.Job, vehicleRoute *route.VehicleRoute) bool {
+	if !s.isRemovable(job) {
+		return false
+	}
+	if vehicleRoute.TourActivities().RemoveJob(job) {
+		s.ruinListeners.Removed(job, vehicleRoute)
+		return true
+	}
+	return false
+}
+
+// isRemovable reports whether job may be removed, i.e. it is neither a break nor fixed in an initial route.
+func (s *AbstractRuinStrategy) isRemovable(job problem.Job) bool {
+	if job.JobType().IsBreak() {
+		return false
+	}
+	_, ok := s.jobs[job.Id()]
+	return ok
+}
+
+// routeJobs returns the jobs of vehicleRoute in the order of their first activity.
+func routeJobs(vehicleRoute *route.VehicleRoute) []problem.Job {
+	jobs := make([]problem.Job, 0, vehicleRoute.TourActivities().JobSize())
+	seen := make(map[problem.Job]bool)
+	for _, act := range vehicleRoute.Activities() {
+		if ja, ok := act.(problem.JobActivity); ok && !seen[ja.Job()] {
+			seen[ja.Job()] = true
+			jobs = append(jobs, ja.Job())
+		}
+	}
+	return jobs
+}
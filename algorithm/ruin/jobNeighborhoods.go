@@ -0,0 +1,96 @@
+package ruin
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/vrp"
+	"sort"
+)
+
+// JobDistance measures how far apart two jobs are.
+type JobDistance interface {
+	Distance(i, j problem.Job) float64
+}
+
+// DefaultJobDistance is the average transport cost between the locations of two jobs. Shipments
+// contribute both their pickup and delivery location.
+type DefaultJobDistance struct {
+	costs cost.VehicleRoutingTransportCosts
+}
+
+func NewDefaultJobDistance(costs cost.VehicleRoutingTransportCosts) *DefaultJobDistance {
+	return &DefaultJobDistance{costs: costs}
+}
+
+func (d *DefaultJobDistance) Distance(i, j problem.Job) float64 {
+	if i == j {
+		return 0.
+	}
+	sum := 0.
+	n := 0
+	for _, from := range jobLocations(i) {
+		for _, to := range jobLocations(j) {
+			sum += d.costs.TransportCost(from, to, 0., nil, nil)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0.
+	}
+	return sum / float64(n)
+}
+
+func jobLocations(job problem.Job) []*problem.Location {
+	locations := make([]*problem.Location, 0, len(job.Activities()))
+	for _, act := range job.Activities() {
+		if act.Location() != nil {
+			locations = append(locations, act.Location())
+		}
+	}
+	return locations
+}
+
+// JobNeighborhoods holds, for every job, the other jobs of the problem ordered by their distance to it.
+type JobNeighborhoods struct {
+	neighbors map[problem.Job][]problem.Job
+}
+
+// NewJobNeighborhoods precomputes the capacity nearest neighbors of every job in vrp.
+func NewJobNeighborhoods(vrp *vrp.VehicleRoutingProblem, distance JobDistance, capacity int) *JobNeighborhoods {
+	jobs := vrp.JobsOrderedByIndex()
+	res := &JobNeighborhoods{
+		neighbors: make(map[problem.Job][]problem.Job, len(jobs)),
+	}
+	for _, i := range jobs {
+		type neighbor struct {
+			job      problem.Job
+			distance float64
+		}
+		candidates := make([]neighbor, 0, len(jobs)-1)
+		for _, j := range jobs {
+			if i == j {
+				continue
+			}
+			candidates = append(candidates, neighbor{job: j, distance: distance.Distance(i, j)})
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].distance < candidates[b].distance
+		})
+		n := min(capacity, len(candidates))
+		nearest := make([]problem.Job, n)
+		for k := 0; k < n; k++ {
+			nearest[k] = candidates[k].job
+		}
+		res.neighbors[i] = nearest
+	}
+	return res
+}
+
+// NearestNeighbors returns at most n jobs closest to job, nearest first.
+func (h *JobNeighborhoods) NearestNeighbors(n int, job problem.Job) []problem.Job {
+	neighbors := h.neighbors[job]
+	n = min(n, len(neighbors))
+	c := make([]problem.Job, n)
+	copy(c, neighbors[:n])
+	return c
+}
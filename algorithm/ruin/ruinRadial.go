@@ -0,0 +1,55 @@
+package ruin
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ RuinStrategy = (*RadialRuin)(nil)
+
+// RadialRuin removes a randomly selected seed job together with its nearest neighbors.
+type RadialRuin struct {
+	AbstractRuinStrategy
+	jobs             []problem.Job
+	jobNeighborhoods *JobNeighborhoods
+}
+
+// NewRadialRuin removes the given fraction of all jobs in vrp around a random seed job. The neighborhood
+// of a job is determined by the transport costs of vrp.
+func NewRadialRuin(vrp *vrp.VehicleRoutingProblem, fraction float64) *RadialRuin {
+	return NewRadialRuinWithNeighborhoods(vrp, fraction, NewJobNeighborhoods(vrp, NewDefaultJobDistance(vrp.TransportCosts()), len(vrp.Jobs())))
+}
+
+func NewRadialRuinWithNeighborhoods(vrp *vrp.VehicleRoutingProblem, fraction float64, jobNeighborhoods *JobNeighborhoods) *RadialRuin {
+	res := &RadialRuin{
+		jobs:             vrp.JobsOrderedByIndex(),
+		jobNeighborhoods: jobNeighborhoods,
+	}
+	res.AbstractRuinStrategy = newAbstractRuinStrategy(vrp, res)
+	res.SetRuinShareFactory(NewFractionRuinShareFactory(vrp, fraction))
+	return res
+}
+
+func (r *RadialRuin) ruinRoutes(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	unassignedJobs := make([]problem.Job, 0)
+	nOfJobs2BeRemoved := min(r.numberToBeRemoved(), len(r.jobs))
+	if nOfJobs2BeRemoved == 0 {
+		return unassignedJobs
+	}
+	seed := r.jobs[r.random.IntN(len(r.jobs))]
+	if r.removeJob(seed, vehicleRoutes) {
+		unassignedJobs = append(unassignedJobs, seed)
+	}
+	for _, neighbor := range r.jobNeighborhoods.NearestNeighbors(nOfJobs2BeRemoved-1, seed) {
+		if r.removeJob(neighbor, vehicleRoutes) {
+			unassignedJobs = append(unassignedJobs, neighbor)
+		}
+	}
+	return unassignedJobs
+}
+
+func (r *RadialRuin) String() string {
+	return fmt.Sprintf("[name=radialRuin][noJobsToBeRemoved=%v]", r.ruinShareFactory)
+}
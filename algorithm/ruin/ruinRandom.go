@@ -0,0 +1,49 @@
+package ruin
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+var _ RuinStrategy = (*RandomRuin)(nil)
+
+// RandomRuin removes randomly selected jobs from the routes.
+type RandomRuin struct {
+	AbstractRuinStrategy
+	jobs []problem.Job
+}
+
+// NewRandomRuin removes the given fraction of all jobs in vrp.
+func NewRandomRuin(vrp *vrp.VehicleRoutingProblem, fraction float64) *RandomRuin {
+	res := &RandomRuin{
+		jobs: vrp.JobsOrderedByIndex(),
+	}
+	res.AbstractRuinStrategy = newAbstractRuinStrategy(vrp, res)
+	res.SetRuinShareFactory(NewFractionRuinShareFactory(vrp, fraction))
+	return res
+}
+
+func (r *RandomRuin) ruinRoutes(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	unassignedJobs := make([]problem.Job, 0)
+	nOfJobs2BeRemoved := min(r.numberToBeRemoved(), len(r.jobs))
+	if nOfJobs2BeRemoved == 0 {
+		return unassignedJobs
+	}
+	candidates := make([]problem.Job, len(r.jobs))
+	copy(candidates, r.jobs)
+	r.random.Shuffle(len(candidates), func(i, k int) {
+		candidates[i], candidates[k] = candidates[k], candidates[i]
+	})
+	for _, job := range candidates[:nOfJobs2BeRemoved] {
+		if r.removeJob(job, vehicleRoutes) {
+			unassignedJobs = append(unassignedJobs, job)
+		}
+	}
+	return unassignedJobs
+}
+
+func (r *RandomRuin) String() string {
+	return fmt.Sprintf("[name=randomRuin][noJobsToBeRemoved=%v]", r.ruinShareFactory)
+}
@@ -0,0 +1,69 @@
+package ruin
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+)
+
+// RuinStrategy removes jobs from a collection of vehicle routes.
+type RuinStrategy interface {
+	// Ruin removes jobs from the given routes and returns the removed jobs.
+	Ruin(vehicleRoutes []*route.VehicleRoute) []problem.Job
+	AddListener(l RuinListener)
+	RemoveListener(l RuinListener)
+	Listeners() []RuinListener
+}
+
+// RuinListener is informed about the start and end of a ruin as well as about every removed job.
+type RuinListener interface {
+	RuinStarts(routes []*route.VehicleRoute)
+	RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job)
+	Removed(job problem.Job, fromRoute *route.VehicleRoute)
+}
+
+type RuinListeners struct {
+	ruinListeners []RuinListener
+}
+
+func NewRuinListeners() *RuinListeners {
+	return &RuinListeners{
+		ruinListeners: make([]RuinListener, 0),
+	}
+}
+
+func (l *RuinListeners) RuinStarts(routes []*route.VehicleRoute) {
+	for _, rl := range l.ruinListeners {
+		rl.RuinStarts(routes)
+	}
+}
+
+func (l *RuinListeners) RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	for _, rl := range l.ruinListeners {
+		rl.RuinEnds(routes, unassignedJobs)
+	}
+}
+
+func (l *RuinListeners) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	for _, rl := range l.ruinListeners {
+		rl.Removed(job, fromRoute)
+	}
+}
+
+func (l *RuinListeners) AddListener(ruinListener RuinListener) {
+	l.ruinListeners = append(l.ruinListeners, ruinListener)
+}
+
+func (l *RuinListeners) RemoveListener(ruinListener RuinListener) {
+	for i, rl := range l.ruinListeners {
+		if rl == ruinListener {
+			l.ruinListeners = append(l.ruinListeners[:i], l.ruinListeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *RuinListeners) Listeners() []RuinListener {
+	c := make([]RuinListener, len(l.ruinListeners))
+	copy(c, l.ruinListeners)
+	return c
+}
@@ -0,0 +1,187 @@
+package ruin
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var _ RuinStrategy = (*StringRuin)(nil)
+
+// StringRuin implements the ruin of Slack Induction by String Removals (SISR) by Christiaens and Vanden
+// Berghe (2020). Starting with a random seed job, it visits the seed and its nearest neighbors and removes
+// one string of consecutive activities from each route it meets until enough strings are removed. A string is
+// removed as a whole or, with probability splitRate, split: a random block of activities in the middle of the
+// string is preserved and only the activities before and after the block are removed.
+//
+// Removing an activity removes its job, i.e. if one side of a shipment is part of a string, the other side is
+// removed as well.
+//
+// The ruin share is the average number of removed jobs (c̄ in the paper).
+type StringRuin struct {
+	AbstractRuinStrategy
+	jobNeighborhoods *JobNeighborhoods
+	maxStringLength  int
+	splitRate        float64
+	splitDepth       float64
+}
+
+// NewStringRuin creates SISR with an average of 10 removed jobs, a maximum string length of 10,
+// a split rate of 0.5 and a split depth of 0.01.
+func NewStringRuin(vrp *vrp.VehicleRoutingProblem) *StringRuin {
+	return NewStringRuinWithNeighborhoods(vrp, NewJobNeighborhoods(vrp, NewDefaultJobDistance(vrp.TransportCosts()), len(vrp.Jobs())))
+}
+
+func NewStringRuinWithNeighborhoods(vrp *vrp.VehicleRoutingProblem, jobNeighborhoods *JobNeighborhoods) *StringRuin {
+	res := &StringRuin{
+		jobNeighborhoods: jobNeighborhoods,
+		maxStringLength:  10,
+		splitRate:        0.5,
+		splitDepth:       0.01,
+	}
+	res.AbstractRuinStrategy = newAbstractRuinStrategy(vrp, res)
+	res.SetRuinShareFactory(NewRuinShareFactoryImpl(10, 10))
+	return res
+}
+
+// SetMaxStringLength sets the maximum number of activities of a string (L^max in the paper).
+func (r *StringRuin) SetMaxStringLength(maxStringLength int) {
+	if maxStringLength < 1 {
+		panic("max string length must be >= 1")
+	}
+	r.maxStringLength = maxStringLength
+}
+
+// SetSplitRate sets the probability that a string is split instead of removed as a whole.
+func (r *StringRuin) SetSplitRate(splitRate float64) {
+	if splitRate < 0. || splitRate > 1. {
+		panic("split rate must be within [0,1]")
+	}
+	r.splitRate = splitRate
+}
+
+// SetSplitDepth sets the probability to stop growing the preserved block of a split string (β in the paper).
+// The smaller it is, the more activities are preserved.
+func (r *StringRuin) SetSplitDepth(splitDepth float64) {
+	if splitDepth < 0. || splitDepth > 1. {
+		panic("split depth must be within [0,1]")
+	}
+	r.splitDepth = splitDepth
+}
+
+func (r *StringRuin) ruinRoutes(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	unassignedJobs := make([]problem.Job, 0)
+	routeOfJob := make(map[problem.Job]*route.VehicleRoute)
+	seedCandidates := make([]problem.Job, 0)
+	nOfActivities := 0
+	nOfNonEmptyRoutes := 0
+	for _, vr := range vehicleRoutes {
+		if vr.IsEmpty() {
+			continue
+		}
+		nOfNonEmptyRoutes++
+		nOfActivities += len(vr.Activities())
+		for _, job := range routeJobs(vr) {
+			routeOfJob[job] = vr
+			if r.isRemovable(job) {
+				seedCandidates = append(seedCandidates, job)
+			}
+		}
+	}
+	if len(seedCandidates) == 0 {
+		return unassignedJobs
+	}
+
+	avgTourCardinality := float64(nOfActivities) / float64(nOfNonEmptyRoutes)
+	maxStringLength := math.Min(float64(r.maxStringLength), avgTourCardinality)
+	avgRemovedJobs := float64(r.numberToBeRemoved())
+	maxStrings := 4.*avgRemovedJobs/(1.+maxStringLength) - 1.
+	nOfStrings := int(math.Floor(1. + r.random.Float64()*maxStrings))
+	if nOfStrings < 1 {
+		nOfStrings = 1
+	}
+
+	seed := seedCandidates[r.random.IntN(len(seedCandidates))]
+	ruinedRoutes := make(map[*route.VehicleRoute]bool)
+	candidates := append([]problem.Job{seed}, r.jobNeighborhoods.NearestNeighbors(math.MaxInt, seed)...)
+	for _, job := range candidates {
+		if len(ruinedRoutes) >= nOfStrings {
+			break
+		}
+		vr, routed := routeOfJob[job]
+		if !routed || ruinedRoutes[vr] || !vr.TourActivities().ServesJob(job) {
+			continue
+		}
+		ruinedRoutes[vr] = true
+		unassignedJobs = append(unassignedJobs, r.ruinString(vr, job, maxStringLength)...)
+	}
+	return unassignedJobs
+}
+
+// ruinString removes one string of activities containing an activity of job from vr.
+func (r *StringRuin) ruinString(vr *route.VehicleRoute, job problem.Job, maxStringLength float64) []problem.Job {
+	acts := append([]problem.TourActivity{}, vr.Activities()...)
+	position := activityPosition(acts, job)
+	maxLength := int(math.Min(float64(len(acts)), maxStringLength))
+	if maxLength < 1 {
+		maxLength = 1
+	}
+	stringLength := 1 + r.random.IntN(maxLength)
+
+	var toRemove []problem.TourActivity
+	if stringLength == len(acts) || r.random.Float64() >= r.splitRate {
+		start := r.stringStart(position, stringLength, len(acts))
+		toRemove = acts[start : start+stringLength]
+	} else {
+		preserved := 1
+		for stringLength+preserved < len(acts) && r.random.Float64() > r.splitDepth {
+			preserved++
+		}
+		totalLength := stringLength + preserved
+		start := r.stringStart(position, totalLength, len(acts))
+		// the preserved block is put between removed activities whenever the string is long enough
+		offset := 1
+		if stringLength > 1 {
+			offset = 1 + r.random.IntN(stringLength-1)
+		} else if r.random.IntN(2) == 0 {
+			offset = 0
+		}
+		toRemove = append(toRemove, acts[start:start+offset]...)
+		toRemove = append(toRemove, acts[start+offset+preserved:start+totalLength]...)
+	}
+
+	removed := make([]problem.Job, 0, len(toRemove))
+	for _, act := range toRemove {
+		ja, ok := act.(problem.JobActivity)
+		if !ok {
+			continue
+		}
+		if r.removeJobFromRoute(ja.Job(), vr) {
+			removed = append(removed, ja.Job())
+		}
+	}
+	return removed
+}
+
+// stringStart draws the first position of a string of the given length that covers position.
+func (r *StringRuin) stringStart(position, length, nOfActivities int) int {
+	lowest := max(0, position-length+1)
+	highest := min(position, nOfActivities-length)
+	return lowest + r.random.IntN(highest-lowest+1)
+}
+
+func activityPosition(acts []problem.TourActivity, job problem.Job) int {
+	for i, act := range acts {
+		if ja, ok := act.(problem.JobActivity); ok && ja.Job() == job {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *StringRuin) String() string {
+	return fmt.Sprintf("[name=stringRuin][avgNoJobsToBeRemoved=%v][maxStringLength=%d][splitRate=%.2f][splitDepth=%.2f]",
+		r.ruinShareFactory, r.maxStringLength, r.splitRate, r.splitDepth)
+}
@@ -0,0 +1,127 @@
+package ruin
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+	"math"
+	"sort"
+)
+
+var _ RuinStrategy = (*WorstRuin)(nil)
+
+// WorstRuin removes the jobs whose removal saves the most transport costs. Jobs are removed one at a time
+// and savings are recalculated after each removal. To diversify the search the job to be removed is drawn
+// from the candidates sorted by savings with the bias y^randomizationDegree (Ropke and Pisinger 2006), where
+// y is uniform in [0,1). The larger the degree, the greedier the selection.
+type WorstRuin struct {
+	AbstractRuinStrategy
+	randomizationDegree float64
+}
+
+// NewWorstRuin removes the given fraction of all jobs in vrp.
+func NewWorstRuin(vrp *vrp.VehicleRoutingProblem, fraction float64) *WorstRuin {
+	res := &WorstRuin{
+		randomizationDegree: 3.,
+	}
+	res.AbstractRuinStrategy = newAbstractRuinStrategy(vrp, res)
+	res.SetRuinShareFactory(NewFractionRuinShareFactory(vrp, fraction))
+	return res
+}
+
+func (r *WorstRuin) SetRandomizationDegree(degree float64) {
+	if degree < 1. {
+		panic("randomization degree must be >= 1")
+	}
+	r.randomizationDegree = degree
+}
+
+type jobSavings struct {
+	job     problem.Job
+	route   *route.VehicleRoute
+	savings float64
+}
+
+func (r *WorstRuin) ruinRoutes(vehicleRoutes []*route.VehicleRoute) []problem.Job {
+	unassignedJobs := make([]problem.Job, 0)
+	nOfJobs2BeRemoved := r.numberToBeRemoved()
+	for len(unassignedJobs) < nOfJobs2BeRemoved {
+		candidates := r.savings(vehicleRoutes)
+		if len(candidates) == 0 {
+			break
+		}
+		selected := candidates[int(math.Pow(r.random.Float64(), r.randomizationDegree)*float64(len(candidates)))]
+		if r.removeJobFromRoute(selected.job, selected.route) {
+			unassignedJobs = append(unassignedJobs, selected.job)
+		}
+	}
+	return unassignedJobs
+}
+
+// savings returns the removable jobs in vehicleRoutes sorted by their savings in descending order. The savings of
+// a job are the transport costs saved by removing all its activities at once, thus the arc between a pickup and a
+// delivery following each other is counted once.
+func (r *WorstRuin) savings(vehicleRoutes []*route.VehicleRoute) []jobSavings {
+	res := make([]jobSavings, 0)
+	for _, vr := range vehicleRoutes {
+		indices := make(map[problem.Job][]int)
+		for k, act := range vr.Activities() {
+			if ja, ok := act.(problem.JobActivity); ok && r.isRemovable(ja.Job()) {
+				indices[ja.Job()] = append(indices[ja.Job()], k)
+			}
+		}
+		for _, job := range routeJobs(vr) {
+			jobIndices, ok := indices[job]
+			if !ok {
+				continue
+			}
+			savings := 0.
+			// activities following each other are removed as one segment
+			for first := 0; first < len(jobIndices); {
+				last := first
+				for last+1 < len(jobIndices) && jobIndices[last+1] == jobIndices[last]+1 {
+					last++
+				}
+				savings += r.segmentSavings(vr, jobIndices[first], jobIndices[last])
+				first = last + 1
+			}
+			res = append(res, jobSavings{job: job, route: vr, savings: savings})
+		}
+	}
+	sort.SliceStable(res, func(i, k int) bool {
+		return res[i].savings > res[k].savings
+	})
+	return res
+}
+
+// segmentSavings returns the transport costs saved by removing the activities of vr from index first to index
+// last, inclusively.
+func (r *WorstRuin) segmentSavings(vr *route.VehicleRoute, first, last int) float64 {
+	costs := r.vrp.TransportCosts()
+	acts := vr.Activities()
+	arcCost := func(from, to problem.TourActivity) float64 {
+		return costs.TransportCost(from.Location(), to.Location(), from.EndTime(), vr.Driver(), vr.Vehicle())
+	}
+	var prev, next problem.TourActivity = vr.Start(), nil
+	if first > 0 {
+		prev = acts[first-1]
+	}
+	if last < len(acts)-1 {
+		next = acts[last+1]
+	} else if vr.Vehicle().IsReturnToDepot() {
+		next = vr.End()
+	}
+	savings := arcCost(prev, acts[first])
+	for k := first; k < last; k++ {
+		savings += arcCost(acts[k], acts[k+1])
+	}
+	if next != nil {
+		savings += arcCost(acts[last], next) - arcCost(prev, next)
+	}
+	return savings
+}
+
+func (r *WorstRuin) String() string {
+	return fmt.Sprintf("[name=worstRuin][noJobsToBeRemoved=%v]", r.ruinShareFactory)
+}
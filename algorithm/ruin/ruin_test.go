@@ -0,0 +1,232 @@
+package ruin
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+	"gsprit/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingListener struct {
+	starts, ends int
+	removed      []problem.Job
+}
+
+func (l *countingListener) RuinStarts(routes []*route.VehicleRoute) { l.starts++ }
+
+func (l *countingListener) RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	l.ends++
+}
+
+func (l *countingListener) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	l.removed = append(l.removed, job)
+}
+
+func newService(id string, x, y float64) *job.Service {
+	return job.NewServiceBuilder[*job.Service](id).SetLocation(problem.NewLocationWithCoordinate(x, y)).Build()
+}
+
+func newProblemAndRoute(services ...*job.Service) (*vrp.VehicleRoutingProblem, *route.VehicleRoute) {
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	vrpBuilder := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v)
+	routeBuilder := route.NewVehicleRouteBuilder(v, driver.NewNoDriver())
+	for _, s := range services {
+		vrpBuilder.AddJob(s)
+		routeBuilder.AddService(s)
+	}
+	return vrpBuilder.Build(), routeBuilder.Build()
+}
+
+func servicesOnLine(n int) []*job.Service {
+	services := make([]*job.Service, n)
+	for i := range services {
+		services[i] = newService(fmt.Sprintf("s%02d", i), float64(i+1), 0)
+	}
+	return services
+}
+
+func TestRandomRuin_ShouldRemoveFractionOfJobs(t *testing.T) {
+	p, r := newProblemAndRoute(servicesOnLine(10)...)
+	ruin := NewRandomRuin(p, 0.5)
+	l := &countingListener{}
+	ruin.AddListener(l)
+
+	removed := ruin.Ruin([]*route.VehicleRoute{r})
+
+	assert.Len(t, removed, 5)
+	assert.Equal(t, 5, r.TourActivities().JobSize())
+	assert.Equal(t, removed, l.removed)
+	assert.Equal(t, 1, l.starts)
+	assert.Equal(t, 1, l.ends)
+	for _, j := range removed {
+		assert.False(t, r.TourActivities().ServesJob(j))
+	}
+}
+
+func TestRandomRuin_SameSeedShouldRemoveSameJobs(t *testing.T) {
+	removedWithSeed := func(seed uint64) []problem.Job {
+		p, r := newProblemAndRoute(servicesOnLine(10)...)
+		ruin := NewRandomRuin(p, 0.3)
+		ruin.SetRandom(util.NewRandom(seed))
+		return ruin.Ruin([]*route.VehicleRoute{r})
+	}
+	first := removedWithSeed(42)
+	second := removedWithSeed(42)
+	assert.Equal(t, len(first), len(second))
+	for i := range first {
+		assert.Equal(t, first[i].Id(), second[i].Id())
+	}
+}
+
+func TestRandomRuin_ShareFactoryShouldDetermineNumberOfRemovedJobs(t *testing.T) {
+	p, r := newProblemAndRoute(servicesOnLine(10)...)
+	ruin := NewRandomRuin(p, 0.5)
+	ruin.SetRuinShareFactory(NewRuinShareFactoryImpl(2, 2))
+
+	assert.Len(t, ruin.Ruin([]*route.VehicleRoute{r}), 2)
+}
+
+func TestRadialRuin_ShouldRemoveSeedAndItsNeighbors(t *testing.T) {
+	services := []*job.Service{
+		newService("a1", 1, 0), newService("a2", 2, 0), newService("a3", 3, 0),
+		newService("b1", 100, 0), newService("b2", 101, 0), newService("b3", 102, 0),
+	}
+	p, r := newProblemAndRoute(services...)
+	ruin := NewRadialRuin(p, 0.5)
+
+	removed := ruin.Ruin([]*route.VehicleRoute{r})
+
+	assert.Len(t, removed, 3)
+	cluster := removed[0].Id()[0]
+	for _, j := range removed {
+		assert.Equal(t, cluster, j.Id()[0])
+	}
+}
+
+func TestWorstRuin_ShouldRemoveJobWithHighestSavings(t *testing.T) {
+	outlier := newService("outlier", 2, 50)
+	p, r := newProblemAndRoute(newService("s1", 1, 0), newService("s2", 2, 0), outlier, newService("s3", 3, 0))
+	ruin := NewWorstRuin(p, 0.25)
+	ruin.SetRandomizationDegree(1000)
+
+	removed := ruin.Ruin([]*route.VehicleRoute{r})
+
+	assert.Equal(t, []problem.Job{outlier}, removed)
+	assert.Equal(t, 3, r.TourActivities().JobSize())
+}
+
+func TestWorstRuin_SavingsOfShipmentShouldCountArcBetweenAdjacentPickupAndDeliveryOnce(t *testing.T) {
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	s := newService("s", 10, 0)
+	sh := job.NewShipmentBuilder("sh").SetPickupLocation(problem.NewLocationWithCoordinate(10, 50)).
+		SetDeliveryLocation(problem.NewLocationWithCoordinate(10, 51)).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(s).AddJob(sh).Build()
+	r := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).AddService(s).AddPickupForShipment(sh).
+		AddDeliveryForShipment(sh).Build()
+
+	savings := NewWorstRuin(p, 0.5).savings([]*route.VehicleRoute{r})
+
+	// s -> pickup -> delivery -> depot is replaced by s -> depot
+	assert.Equal(t, sh, savings[0].job)
+	assert.InDelta(t, 50.+1.+math.Hypot(10., 51.)-10., savings[0].savings, 1e-9)
+}
+
+func TestStringRuin_WithoutSplitShouldRemoveConsecutiveActivities(t *testing.T) {
+	services := servicesOnLine(20)
+	p, r := newProblemAndRoute(services...)
+	ruin := NewStringRuin(p)
+	ruin.SetSplitRate(0.)
+	ruin.SetMaxStringLength(5)
+	ruin.SetRuinShareFactory(NewRuinShareFactoryImpl(3, 3))
+
+	removed := ruin.Ruin([]*route.VehicleRoute{r})
+
+	assert.NotEmpty(t, removed)
+	assert.LessOrEqual(t, len(removed), 5)
+	positions := make([]int, 0, len(removed))
+	for _, j := range removed {
+		for i, s := range services {
+			if s == j {
+				positions = append(positions, i)
+			}
+		}
+	}
+	for i := 1; i < len(positions); i++ {
+		assert.Equal(t, positions[i-1]+1, positions[i])
+	}
+}
+
+func TestStringRuin_ShouldRemoveStringsFromSeveralRoutes(t *testing.T) {
+	v1 := vehicle.NewVehicleBuilder("v1").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	v2 := vehicle.NewVehicleBuilder("v2").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	vrpBuilder := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v1).AddVehicle(v2)
+	rb1 := route.NewVehicleRouteBuilder(v1, driver.NewNoDriver())
+	rb2 := route.NewVehicleRouteBuilder(v2, driver.NewNoDriver())
+	for i := 0; i < 10; i++ {
+		s1 := newService(fmt.Sprintf("a%02d", i), float64(i), 1)
+		s2 := newService(fmt.Sprintf("b%02d", i), float64(i), -1)
+		vrpBuilder.AddJob(s1).AddJob(s2)
+		rb1.AddService(s1)
+		rb2.AddService(s2)
+	}
+	p := vrpBuilder.Build()
+	routes := []*route.VehicleRoute{rb1.Build(), rb2.Build()}
+	ruin := NewStringRuin(p)
+	ruin.SetMaxStringLength(2)
+	ruin.SetRuinShareFactory(NewRuinShareFactoryImpl(20, 20))
+
+	removed := ruin.Ruin(routes)
+
+	assert.Less(t, routes[0].TourActivities().JobSize(), 10)
+	assert.Less(t, routes[1].TourActivities().JobSize(), 10)
+	assert.Equal(t, 20, len(removed)+routes[0].TourActivities().JobSize()+routes[1].TourActivities().JobSize())
+}
+
+func TestStringRuin_ShouldKeepShipmentsConsistent(t *testing.T) {
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build()
+	vrpBuilder := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v)
+	routeBuilder := route.NewVehicleRouteBuilder(v, driver.NewNoDriver())
+	shipments := make([]*job.Shipment, 6)
+	for i := range shipments {
+		shipments[i] = job.NewShipmentBuilder(fmt.Sprintf("s%d", i)).
+			SetPickupLocation(problem.NewLocationWithCoordinate(float64(i), 0)).
+			SetDeliveryLocation(problem.NewLocationWithCoordinate(float64(i), 10)).
+			Build()
+		vrpBuilder.AddJob(shipments[i])
+	}
+	for _, s := range shipments {
+		routeBuilder.AddPickupForShipment(s)
+	}
+	for _, s := range shipments {
+		routeBuilder.AddDeliveryForShipment(s)
+	}
+	p := vrpBuilder.Build()
+
+	for seed := uint64(0); seed < 20; seed++ {
+		r := routeBuilder.Build().Copy()
+		ruin := NewStringRuin(p)
+		ruin.SetRandom(util.NewRandom(seed))
+		ruin.SetRuinShareFactory(NewRuinShareFactoryImpl(3, 3))
+
+		removed := ruin.Ruin([]*route.VehicleRoute{r})
+
+		assert.NotEmpty(t, removed)
+		assert.Equal(t, 2*r.TourActivities().JobSize(), len(r.Activities()))
+		for _, j := range removed {
+			assert.False(t, r.TourActivities().ServesJob(j))
+			for _, act := range r.Activities() {
+				assert.NotEqual(t, j, act.(problem.JobActivity).Job())
+			}
+		}
+	}
+}
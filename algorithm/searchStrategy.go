@@ -9,16 +9,31 @@ import (
 )
 
 type DiscoveredSolution struct {
-	solution   *solution.VehicleRoutingProblemSolution
-	accepted   bool
-	strategyId string
+	solution    *solution.VehicleRoutingProblemSolution
+	accepted    bool
+	improvement bool
+	strategyId  string
 }
 
 func (d *DiscoveredSolution) Solution() *solution.VehicleRoutingProblemSolution {
 	return d.solution
 }
 
-func newDiscoveredSolution(solution *solution.VehicleRoutingProblemSolution, accepted bool, strategyId string) *DiscoveredSolution {
+// IsAccepted reports whether the solution acceptor has accepted the solution.
+func (d *DiscoveredSolution) IsAccepted() bool {
+	return d.accepted
+}
+
+// IsImprovement reports whether the solution is cheaper than the solution the strategy has started from.
+func (d *DiscoveredSolution) IsImprovement() bool {
+	return d.improvement
+}
+
+func (d *DiscoveredSolution) StrategyId() string {
+	return d.strategyId
+}
+
+func NewDiscoveredSolution(solution *solution.VehicleRoutingProblemSolution, accepted bool, strategyId string) *DiscoveredSolution {
 	return &DiscoveredSolution{
 		solution:   solution,
 		accepted:   accepted,
@@ -70,21 +85,64 @@ func (s *SearchStrategy) String() string {
 	return fmt.Sprintf("searchStrategy [#modules=%d][selector=%v][acceptor=%v]", len(s.searchStrategyModules), s.solutionSelector, s.solutionAcceptor)
 }
 
+// Run selects a solution from solutions, applies the modules to a copy of it and passes the result to the acceptor,
+// which may replace one of solutions by it. Use RunOnPool to let acceptors add it to solutions as well.
 func (s *SearchStrategy) Run(vrp *vrp.VehicleRoutingProblem, solutions []*solution.VehicleRoutingProblemSolution) (*DiscoveredSolution, error) {
-	solution := s.solutionSelector.SelectSolution(solutions)
-	if solution == nil {
+	selected, err := s.Select(solutions)
+	if err != nil {
+		return nil, err
+	}
+	newSolution := s.Improve(selected.Copy())
+	return s.discovered(selected, newSolution, s.solutionAcceptor.AcceptSolution(solutions, newSolution)), nil
+}
+
+// RunOnPool is Run with a pool an acceptor.SolutionPoolAcceptor may add the new solution to.
+func (s *SearchStrategy) RunOnPool(vrp *vrp.VehicleRoutingProblem, solutions *[]*solution.VehicleRoutingProblemSolution) (*DiscoveredSolution, error) {
+	selected, err := s.Select(*solutions)
+	if err != nil {
+		return nil, err
+	}
+	return s.Accept(solutions, selected, s.Improve(selected.Copy())), nil
+}
+
+// Select selects the solution to start from.
+func (s *SearchStrategy) Select(solutions []*solution.VehicleRoutingProblemSolution) (*solution.VehicleRoutingProblemSolution, error) {
+	selected := s.solutionSelector.SelectSolution(solutions)
+	if selected == nil {
 		return nil, fmt.Errorf("solution is nil. check solutionSelector to return an appropriate solution. " +
 			"figure out whether you start with an initial solution. either you set it manually by algorithm.AddInitialSolution(...)" +
 			" or let the algorithm create an initial solution for you. then add the <construction>...</construction> xml-snippet to your algorithm's config file")
 	}
-	lastSolution := solution.Copy()
+	return selected, nil
+}
+
+// Improve applies the modules to sol, which is changed in place, and calculates the costs of the result. It does
+// not touch the solution pool, hence it can run concurrently to Select and Accept of other strategies.
+func (s *SearchStrategy) Improve(sol *solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	lastSolution := sol
 	for _, module := range s.searchStrategyModules {
 		lastSolution = module.RunAndGetSolution(lastSolution)
 	}
-	costs := s.solutionCostCalculator.Costs(lastSolution)
-	lastSolution.SetCost(costs)
-	solutionAccepted := s.solutionAcceptor.AcceptSolution(solutions, lastSolution)
-	return newDiscoveredSolution(lastSolution, solutionAccepted, s.Id()), nil
+	lastSolution.SetCost(s.solutionCostCalculator.Costs(lastSolution))
+	return lastSolution
+}
+
+// Accept passes newSolution, which has been derived from selected, to the acceptor, which may put it into
+// solutions. Only an acceptor.SolutionPoolAcceptor may add it to solutions, others may replace a solution only.
+func (s *SearchStrategy) Accept(solutions *[]*solution.VehicleRoutingProblemSolution, selected, newSolution *solution.VehicleRoutingProblemSolution) *DiscoveredSolution {
+	var solutionAccepted bool
+	if poolAcceptor, ok := s.solutionAcceptor.(acceptor.SolutionPoolAcceptor); ok {
+		solutionAccepted = poolAcceptor.AcceptSolutionIntoPool(solutions, newSolution)
+	} else {
+		solutionAccepted = s.solutionAcceptor.AcceptSolution(*solutions, newSolution)
+	}
+	return s.discovered(selected, newSolution, solutionAccepted)
+}
+
+func (s *SearchStrategy) discovered(selected, newSolution *solution.VehicleRoutingProblemSolution, accepted bool) *DiscoveredSolution {
+	discoveredSolution := NewDiscoveredSolution(newSolution, accepted, s.Id())
+	discoveredSolution.improvement = newSolution.Cost() < selected.Cost()
+	return discoveredSolution
 }
 
 func (s *SearchStrategy) AddModule(module SearchStrategyModule) error {
@@ -3,6 +3,7 @@ package algorithm
 import (
 	"errors"
 	"fmt"
+	"gsprit/util"
 	"math/rand/v2"
 )
 
@@ -16,9 +17,35 @@ type SearchStrategyManager struct {
 	strategyIndex           int
 }
 
+func NewSearchStrategyManager() *SearchStrategyManager {
+	return &SearchStrategyManager{
+		searchStrategyListeners: make([]SearchStrategyListener, 0),
+		strategies:              make([]*SearchStrategy, 0),
+		weights:                 make([]float64, 0),
+		id2index:                make(map[string]int),
+		random:                  util.NewRandom(util.DefaultSeed),
+	}
+}
+
 func (m *SearchStrategyManager) SetRandom(r *rand.Rand) {
 	m.random = r
 }
+
+func (m *SearchStrategyManager) Strategies() []*SearchStrategy {
+	c := make([]*SearchStrategy, len(m.strategies))
+	copy(c, m.strategies)
+	return c
+}
+
+// Strategy returns the strategy with the given id.
+func (m *SearchStrategyManager) Strategy(strategyId string) (*SearchStrategy, bool) {
+	index, exists := m.id2index[strategyId]
+	if !exists {
+		return nil, false
+	}
+	return m.strategies[index], true
+}
+
 func (m *SearchStrategyManager) Weights() []float64 {
 	c := make([]float64, len(m.weights))
 	copy(c, m.weights)
@@ -0,0 +1,77 @@
+package algorithm
+
+import (
+	"gsprit/algorithm/acceptor"
+	"gsprit/algorithm/selector"
+	"gsprit/problem/solution"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ acceptor.SolutionAcceptor = (*replaceFirstAcceptance)(nil)
+
+// replaceFirstAcceptance replaces the first solution by a cheaper one. It implements acceptor.SolutionAcceptor
+// only, as custom acceptors written against it do.
+type replaceFirstAcceptance struct{}
+
+func (a *replaceFirstAcceptance) AcceptSolution(solutions []*solution.VehicleRoutingProblemSolution, newSolution *solution.VehicleRoutingProblemSolution) bool {
+	if newSolution.Cost() < solutions[0].Cost() {
+		solutions[0] = newSolution
+		return true
+	}
+	return false
+}
+
+func TestSearchStrategy_Accept_ShouldConsultAcceptorsWithoutPool(t *testing.T) {
+	s := NewSearchStrategy("s", nil, &replaceFirstAcceptance{}, nil)
+	selected := solution.NewVehicleRoutingProblemSolution(nil, 100.)
+	solutions := []*solution.VehicleRoutingProblemSolution{selected}
+
+	better := solution.NewVehicleRoutingProblemSolution(nil, 90.)
+	assert.True(t, s.Accept(&solutions, selected, better).IsAccepted())
+	assert.False(t, s.Accept(&solutions, better, solution.NewVehicleRoutingProblemSolution(nil, 95.)).IsAccepted())
+
+	assert.Equal(t, []*solution.VehicleRoutingProblemSolution{better}, solutions)
+}
+
+func TestSearchStrategy_Accept_ShouldLetPoolAcceptorsAddSolutions(t *testing.T) {
+	s := NewSearchStrategy("s", nil, acceptor.NewGreedyAcceptance(2), nil)
+	selected := solution.NewVehicleRoutingProblemSolution(nil, 100.)
+	solutions := []*solution.VehicleRoutingProblemSolution{selected}
+
+	assert.True(t, s.Accept(&solutions, selected, solution.NewVehicleRoutingProblemSolution(nil, 110.)).IsAccepted())
+
+	assert.Len(t, solutions, 2)
+}
+
+// fixedCosts assigns the same costs to every solution.
+type fixedCosts float64
+
+func (c fixedCosts) Costs(sol *solution.VehicleRoutingProblemSolution) float64 {
+	return float64(c)
+}
+
+func TestSearchStrategy_Run_ShouldReplaceSolutionsInPlace(t *testing.T) {
+	s := NewSearchStrategy("s", selector.NewSelectBest(), acceptor.NewGreedyAcceptance(2), fixedCosts(90.))
+	solutions := []*solution.VehicleRoutingProblemSolution{solution.NewVehicleRoutingProblemSolution(nil, 100.)}
+
+	discovered, err := s.Run(nil, solutions)
+
+	assert.NoError(t, err)
+	assert.True(t, discovered.IsAccepted())
+	assert.True(t, discovered.IsImprovement())
+	assert.Same(t, discovered.Solution(), solutions[0])
+}
+
+func TestSearchStrategy_RunOnPool_ShouldLetPoolAcceptorsAddSolutions(t *testing.T) {
+	s := NewSearchStrategy("s", selector.NewSelectBest(), acceptor.NewGreedyAcceptance(2), fixedCosts(110.))
+	solutions := []*solution.VehicleRoutingProblemSolution{solution.NewVehicleRoutingProblemSolution(nil, 100.)}
+
+	discovered, err := s.RunOnPool(nil, &solutions)
+
+	assert.NoError(t, err)
+	assert.True(t, discovered.IsAccepted())
+	assert.Len(t, solutions, 2)
+	assert.Same(t, discovered.Solution(), solutions[1])
+}
@@ -0,0 +1,52 @@
+package selector
+
+import (
+	"gsprit/problem/solution"
+	"gsprit/util"
+	"math/rand/v2"
+)
+
+var _ SolutionSelector = (*RouletteWheelSelection)(nil)
+
+// RouletteWheelSelection selects a solution with a probability proportional to the inverse of its costs, i.e. a
+// solution half as expensive as another one is selected twice as often. Since this is undefined for solutions
+// without positive costs, the cheapest solution is selected if there is one.
+type RouletteWheelSelection struct {
+	random *rand.Rand
+}
+
+func NewRouletteWheelSelection() *RouletteWheelSelection {
+	return &RouletteWheelSelection{
+		random: util.NewRandom(util.DefaultSeed),
+	}
+}
+
+func (s *RouletteWheelSelection) SetRandom(r *rand.Rand) {
+	s.random = r
+}
+
+func (s *RouletteWheelSelection) SelectSolution(solutions []*solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	if len(solutions) == 0 {
+		return nil
+	}
+	sum := 0.
+	for _, sol := range solutions {
+		if sol.Cost() <= 0. {
+			return solution.BestOf(solutions)
+		}
+		sum += 1. / sol.Cost()
+	}
+	r := s.random.Float64() * sum
+	for _, sol := range solutions {
+		r -= 1. / sol.Cost()
+		if r < 0. {
+			return sol
+		}
+	}
+	// rounding errors
+	return solutions[len(solutions)-1]
+}
+
+func (s *RouletteWheelSelection) String() string {
+	return "[name=rouletteWheelSelection]"
+}
@@ -0,0 +1,20 @@
+package selector
+
+import "gsprit/problem/solution"
+
+var _ SolutionSelector = (*SelectBest)(nil)
+
+// SelectBest selects the cheapest solution.
+type SelectBest struct{}
+
+func NewSelectBest() *SelectBest {
+	return &SelectBest{}
+}
+
+func (s *SelectBest) SelectSolution(solutions []*solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	return solution.BestOf(solutions)
+}
+
+func (s *SelectBest) String() string {
+	return "[name=selectBest]"
+}
@@ -0,0 +1,35 @@
+package selector
+
+import (
+	"gsprit/problem/solution"
+	"gsprit/util"
+	"math/rand/v2"
+)
+
+var _ SolutionSelector = (*SelectRandom)(nil)
+
+// SelectRandom selects one of the solutions uniformly at random.
+type SelectRandom struct {
+	random *rand.Rand
+}
+
+func NewSelectRandom() *SelectRandom {
+	return &SelectRandom{
+		random: util.NewRandom(util.DefaultSeed),
+	}
+}
+
+func (s *SelectRandom) SetRandom(r *rand.Rand) {
+	s.random = r
+}
+
+func (s *SelectRandom) SelectSolution(solutions []*solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	if len(solutions) == 0 {
+		return nil
+	}
+	return solutions[s.random.IntN(len(solutions))]
+}
+
+func (s *SelectRandom) String() string {
+	return "[name=selectRandom]"
+}
@@ -0,0 +1,81 @@
+package selector
+
+import (
+	"testing"
+
+	"gsprit/problem/solution"
+	"gsprit/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSolutions(costs ...float64) []*solution.VehicleRoutingProblemSolution {
+	res := make([]*solution.VehicleRoutingProblemSolution, 0, len(costs))
+	for _, c := range costs {
+		res = append(res, solution.NewVehicleRoutingProblemSolution(nil, c))
+	}
+	return res
+}
+
+// selectionCounts selects n times and counts how often each solution has been selected.
+func selectionCounts(s SolutionSelector, solutions []*solution.VehicleRoutingProblemSolution, n int) map[*solution.VehicleRoutingProblemSolution]int {
+	res := make(map[*solution.VehicleRoutingProblemSolution]int)
+	for i := 0; i < n; i++ {
+		res[s.SelectSolution(solutions)]++
+	}
+	return res
+}
+
+func TestSelectBest_ShouldSelectCheapestSolution(t *testing.T) {
+	solutions := newSolutions(30., 10., 20.)
+	assert.Same(t, solutions[1], NewSelectBest().SelectSolution(solutions))
+}
+
+func TestSelectors_ShouldReturnNilWithoutSolutions(t *testing.T) {
+	for _, s := range []SolutionSelector{NewSelectBest(), NewSelectRandom(), NewTournamentSelection(2), NewRouletteWheelSelection()} {
+		assert.Nil(t, s.SelectSolution(nil))
+	}
+}
+
+func TestSelectRandom_ShouldSelectEverySolution(t *testing.T) {
+	solutions := newSolutions(30., 10., 20.)
+	counts := selectionCounts(NewSelectRandom(), solutions, 3000)
+
+	for _, sol := range solutions {
+		assert.InDelta(t, 1000, counts[sol], 100)
+	}
+}
+
+func TestSelectRandom_ShouldBeReproducibleWithSameSeed(t *testing.T) {
+	solutions := newSolutions(30., 10., 20., 40.)
+	s1, s2 := NewSelectRandom(), NewSelectRandom()
+	s1.SetRandom(util.NewRandom(42))
+	s2.SetRandom(util.NewRandom(42))
+
+	for i := 0; i < 20; i++ {
+		assert.Same(t, s1.SelectSolution(solutions), s2.SelectSolution(solutions))
+	}
+}
+
+func TestTournamentSelection_ShouldFavourCheapSolutions(t *testing.T) {
+	solutions := newSolutions(30., 10., 20.)
+	counts := selectionCounts(NewTournamentSelection(2), solutions, 9000)
+
+	// with replacement, the best of two draws is the cheapest with probability 5/9 and the most expensive with 1/9
+	assert.InDelta(t, 5000, counts[solutions[1]], 300)
+	assert.InDelta(t, 1000, counts[solutions[0]], 300)
+	assert.Panics(t, func() { NewTournamentSelection(0) })
+}
+
+func TestRouletteWheelSelection_ShouldSelectProportionallyToInverseCosts(t *testing.T) {
+	solutions := newSolutions(10., 20.)
+	counts := selectionCounts(NewRouletteWheelSelection(), solutions, 3000)
+
+	assert.InDelta(t, 2000, counts[solutions[0]], 150)
+	assert.InDelta(t, 1000, counts[solutions[1]], 150)
+}
+
+func TestRouletteWheelSelection_ShouldSelectBestIfCostsAreNotPositive(t *testing.T) {
+	solutions := newSolutions(10., 0.)
+	assert.Same(t, solutions[1], NewRouletteWheelSelection().SelectSolution(solutions))
+}
@@ -0,0 +1,49 @@
+package selector
+
+import (
+	"fmt"
+	"gsprit/problem/solution"
+	"gsprit/util"
+	"math/rand/v2"
+)
+
+var _ SolutionSelector = (*TournamentSelection)(nil)
+
+// TournamentSelection draws k solutions at random, with replacement, and selects the cheapest of them. The larger
+// k, the stronger the selection favours cheap solutions; with k = 1 it selects at random.
+type TournamentSelection struct {
+	k      int
+	random *rand.Rand
+}
+
+func NewTournamentSelection(k int) *TournamentSelection {
+	if k < 1 {
+		panic("tournament size must be at least 1")
+	}
+	return &TournamentSelection{
+		k:      k,
+		random: util.NewRandom(util.DefaultSeed),
+	}
+}
+
+func (s *TournamentSelection) SetRandom(r *rand.Rand) {
+	s.random = r
+}
+
+func (s *TournamentSelection) SelectSolution(solutions []*solution.VehicleRoutingProblemSolution) *solution.VehicleRoutingProblemSolution {
+	if len(solutions) == 0 {
+		return nil
+	}
+	var best *solution.VehicleRoutingProblemSolution
+	for i := 0; i < s.k; i++ {
+		candidate := solutions[s.random.IntN(len(solutions))]
+		if best == nil || candidate.Cost() < best.Cost() {
+			best = candidate
+		}
+	}
+	return best
+}
+
+func (s *TournamentSelection) String() string {
+	return fmt.Sprintf("[name=tournamentSelection][k=%d]", s.k)
+}
@@ -0,0 +1,304 @@
+package state
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/vrp"
+)
+
+// StateId identifies a state stored by the StateManager.
+type StateId struct {
+	name  string
+	index int
+}
+
+func (id StateId) Name() string {
+	return id.name
+}
+
+func (id StateId) Index() int {
+	return id.index
+}
+
+func (id StateId) String() string {
+	return fmt.Sprintf("[name=%s][index=%d]", id.name, id.index)
+}
+
+// InternalStates are the states maintained by the StateManager itself. They cannot be overwritten by users.
+var InternalStates = struct {
+	LatestOperationStartTime StateId
+	Load                     StateId
+	LoadAtBeginning          StateId
+	LoadAtEnd                StateId
+	MaxLoad                  StateId
+	PastMaxLoad              StateId
+	FutureMaxLoad            StateId
+}{
+	LatestOperationStartTime: StateId{name: "latest_operation_start_time", index: 0},
+	Load:                     StateId{name: "load", index: 1},
+	LoadAtBeginning:          StateId{name: "load_at_beginning", index: 2},
+	LoadAtEnd:                StateId{name: "load_at_end", index: 3},
+	MaxLoad:                  StateId{name: "max_load", index: 4},
+	PastMaxLoad:              StateId{name: "past_max_load", index: 5},
+	FutureMaxLoad:            StateId{name: "future_max_load", index: 6},
+}
+
+const nOfInternalStates = 7
+
+// StateManager caches states of activities and routes, e.g. the load at an activity or the latest time an
+// activity can start without making the rest of its route infeasible. Arrival and end times are stored on the
+// activities themselves.
+//
+// States are (re)computed by state updaters whenever a route changes. To get informed about changes, the
+// StateManager listens to ruin and insertion events: when a ruin or an insertion starts, the states of all
+// routes are recomputed, afterwards only the states of the route a job has been removed from or inserted into.
+type StateManager struct {
+	vrp                     *vrp.VehicleRoutingProblem
+	stateIds                map[string]StateId
+	nextIndex               int
+	activityStates          map[problem.TourActivity][]any
+	routeStates             map[*route.VehicleRoute][]any
+	activityVisitors        []ActivityVisitor
+	reverseActivityVisitors []ReverseActivityVisitor
+	routeVisitors           []RouteVisitor
+}
+
+// NewStateManager creates a StateManager that updates arrival and end times, latest operation start times and loads.
+func NewStateManager(vrp *vrp.VehicleRoutingProblem) *StateManager {
+	sm := &StateManager{
+		vrp:            vrp,
+		stateIds:       make(map[string]StateId),
+		nextIndex:      nOfInternalStates,
+		activityStates: make(map[problem.TourActivity][]any),
+		routeStates:    make(map[*route.VehicleRoute][]any),
+	}
+	sm.AddActivityVisitor(NewUpdateActivityTimes(vrp.TransportCosts(), vrp.ActivityCosts()))
+	sm.AddReverseActivityVisitor(NewUpdateLatestOperationStartTime(sm, vrp.TransportCosts(), vrp.ActivityCosts()))
+	sm.AddActivityVisitor(NewUpdateLoads(sm))
+	sm.AddActivityVisitor(NewUpdatePastMaxLoad(sm))
+	sm.AddReverseActivityVisitor(NewUpdateFutureMaxLoad(sm))
+	return sm
+}
+
+// CreateStateId creates the id of a custom state. If a state with the given name already exists, its id is returned.
+func (sm *StateManager) CreateStateId(name string) StateId {
+	if id, ok := sm.stateIds[name]; ok {
+		return id
+	}
+	if isInternalStateName(name) {
+		panic(fmt.Sprintf("state name %s is reserved for internal states", name))
+	}
+	id := StateId{name: name, index: sm.nextIndex}
+	sm.nextIndex++
+	sm.stateIds[name] = id
+	return id
+}
+
+// LookupStateId returns the id of the custom state with the given name if it has been created.
+func (sm *StateManager) LookupStateId(name string) (StateId, bool) {
+	id, ok := sm.stateIds[name]
+	return id, ok
+}
+
+func isInternalStateName(name string) bool {
+	switch name {
+	case InternalStates.LatestOperationStartTime.name, InternalStates.Load.name, InternalStates.LoadAtBeginning.name,
+		InternalStates.LoadAtEnd.name, InternalStates.MaxLoad.name, InternalStates.PastMaxLoad.name, InternalStates.FutureMaxLoad.name:
+		return true
+	}
+	return false
+}
+
+// AddActivityVisitor registers a state updater visiting the activities of a route from start to end.
+// Forward visitors run in the order they have been added, followed by the reverse visitors and the route visitors.
+func (sm *StateManager) AddActivityVisitor(v ActivityVisitor) {
+	sm.activityVisitors = append(sm.activityVisitors, v)
+}
+
+// AddReverseActivityVisitor registers a state updater visiting the activities of a route from end to start.
+func (sm *StateManager) AddReverseActivityVisitor(v ReverseActivityVisitor) {
+	sm.reverseActivityVisitors = append(sm.reverseActivityVisitors, v)
+}
+
+// AddRouteVisitor registers a state updater visiting a route as a whole.
+func (sm *StateManager) AddRouteVisitor(v RouteVisitor) {
+	sm.routeVisitors = append(sm.routeVisitors, v)
+}
+
+// ActivityState returns the state of act identified by id.
+func (sm *StateManager) ActivityState(act problem.TourActivity, id StateId) (any, bool) {
+	states, ok := sm.activityStates[act]
+	if !ok || id.index >= len(states) || states[id.index] == nil {
+		return nil, false
+	}
+	return states[id.index], true
+}
+
+// RouteState returns the state of vehicleRoute identified by id.
+func (sm *StateManager) RouteState(vehicleRoute *route.VehicleRoute, id StateId) (any, bool) {
+	states, ok := sm.routeStates[vehicleRoute]
+	if !ok || id.index >= len(states) || states[id.index] == nil {
+		return nil, false
+	}
+	return states[id.index], true
+}
+
+// PutActivityState stores a custom state of act.
+func (sm *StateManager) PutActivityState(act problem.TourActivity, id StateId, state any) {
+	if id.index < nOfInternalStates {
+		panic(fmt.Sprintf("internal state %s cannot be overwritten", id.name))
+	}
+	sm.putActivityState(act, id, state)
+}
+
+// PutRouteState stores a custom state of vehicleRoute.
+func (sm *StateManager) PutRouteState(vehicleRoute *route.VehicleRoute, id StateId, state any) {
+	if id.index < nOfInternalStates {
+		panic(fmt.Sprintf("internal state %s cannot be overwritten", id.name))
+	}
+	sm.putRouteState(vehicleRoute, id, state)
+}
+
+func (sm *StateManager) putActivityState(act problem.TourActivity, id StateId, state any) {
+	sm.activityStates[act] = putState(sm.activityStates[act], id, state)
+}
+
+func (sm *StateManager) putRouteState(vehicleRoute *route.VehicleRoute, id StateId, state any) {
+	sm.routeStates[vehicleRoute] = putState(sm.routeStates[vehicleRoute], id, state)
+}
+
+func putState(states []any, id StateId, state any) []any {
+	for len(states) <= id.index {
+		states = append(states, nil)
+	}
+	states[id.index] = state
+	return states
+}
+
+// LatestOperationStartTime returns the latest time act can start such that the rest of its route stays feasible.
+func (sm *StateManager) LatestOperationStartTime(act problem.TourActivity) float64 {
+	if s, ok := sm.ActivityState(act, InternalStates.LatestOperationStartTime); ok {
+		return s.(float64)
+	}
+	return act.TheoreticalLatestOperationStartTime()
+}
+
+// Load returns the load of the vehicle after act.
+func (sm *StateManager) Load(act problem.TourActivity) *problem.Capacity {
+	return sm.activityCapacity(act, InternalStates.Load)
+}
+
+// PastMaxLoad returns the maximum load from the start of the route up to and including act.
+func (sm *StateManager) PastMaxLoad(act problem.TourActivity) *problem.Capacity {
+	return sm.activityCapacity(act, InternalStates.PastMaxLoad)
+}
+
+// FutureMaxLoad returns the maximum load from act, inclusively, to the end of the route.
+func (sm *StateManager) FutureMaxLoad(act problem.TourActivity) *problem.Capacity {
+	return sm.activityCapacity(act, InternalStates.FutureMaxLoad)
+}
+
+// LoadAtBeginning returns the load of the vehicle when leaving the depot, i.e. the sum of all deliveries.
+func (sm *StateManager) LoadAtBeginning(vehicleRoute *route.VehicleRoute) *problem.Capacity {
+	return sm.routeCapacity(vehicleRoute, InternalStates.LoadAtBeginning)
+}
+
+// LoadAtEnd returns the load of the vehicle when arriving at the end of the route.
+func (sm *StateManager) LoadAtEnd(vehicleRoute *route.VehicleRoute) *problem.Capacity {
+	return sm.routeCapacity(vehicleRoute, InternalStates.LoadAtEnd)
+}
+
+// MaxLoad returns the maximum load of the vehicle along the route.
+func (sm *StateManager) MaxLoad(vehicleRoute *route.VehicleRoute) *problem.Capacity {
+	return sm.routeCapacity(vehicleRoute, InternalStates.MaxLoad)
+}
+
+func (sm *StateManager) activityCapacity(act problem.TourActivity, id StateId) *problem.Capacity {
+	if s, ok := sm.ActivityState(act, id); ok {
+		return s.(*problem.Capacity)
+	}
+	return problem.NewCapacity(nil)
+}
+
+func (sm *StateManager) routeCapacity(vehicleRoute *route.VehicleRoute, id StateId) *problem.Capacity {
+	if s, ok := sm.RouteState(vehicleRoute, id); ok {
+		return s.(*problem.Capacity)
+	}
+	return problem.NewCapacity(nil)
+}
+
+// Clear removes all states.
+func (sm *StateManager) Clear() {
+	sm.activityStates = make(map[problem.TourActivity][]any)
+	sm.routeStates = make(map[*route.VehicleRoute][]any)
+}
+
+// RemoveRoute removes the states of vehicleRoute and its activities, e.g. of a route built only to check its
+// feasibility.
+func (sm *StateManager) RemoveRoute(vehicleRoute *route.VehicleRoute) {
+	for _, act := range vehicleRoute.Activities() {
+		delete(sm.activityStates, act)
+	}
+	delete(sm.routeStates, vehicleRoute)
+}
+
+// UpdateRoute recomputes the states of vehicleRoute.
+func (sm *StateManager) UpdateRoute(vehicleRoute *route.VehicleRoute) {
+	if vehicleRoute.Start() == nil || vehicleRoute.Start().Location() == nil {
+		return
+	}
+	acts := vehicleRoute.Activities()
+	for _, v := range sm.activityVisitors {
+		v.Begin(vehicleRoute)
+	}
+	for _, act := range acts {
+		for _, v := range sm.activityVisitors {
+			v.Visit(act)
+		}
+	}
+	for _, v := range sm.activityVisitors {
+		v.Finish()
+	}
+
+	for _, v := range sm.reverseActivityVisitors {
+		v.Begin(vehicleRoute)
+	}
+	for i := len(acts) - 1; i >= 0; i-- {
+		for _, v := range sm.reverseActivityVisitors {
+			v.Visit(acts[i])
+		}
+	}
+	for _, v := range sm.reverseActivityVisitors {
+		v.Finish()
+	}
+
+	for _, v := range sm.routeVisitors {
+		v.VisitRoute(vehicleRoute)
+	}
+}
+
+func (sm *StateManager) updateRoutes(vehicleRoutes []*route.VehicleRoute) {
+	sm.Clear()
+	for _, vr := range vehicleRoutes {
+		sm.UpdateRoute(vr)
+	}
+}
+
+func (sm *StateManager) RuinStarts(vehicleRoutes []*route.VehicleRoute) {
+	sm.updateRoutes(vehicleRoutes)
+}
+
+func (sm *StateManager) RuinEnds(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {}
+
+func (sm *StateManager) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	sm.UpdateRoute(fromRoute)
+}
+
+func (sm *StateManager) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	sm.updateRoutes(vehicleRoutes)
+}
+
+func (sm *StateManager) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	sm.UpdateRoute(inRoute)
+}
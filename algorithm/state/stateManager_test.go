@@ -0,0 +1,171 @@
+package state
+
+import (
+	"testing"
+
+	"gsprit/algorithm/ruin"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/driver"
+	"gsprit/problem/job"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ruin.RuinListener = (*StateManager)(nil)
+
+type fixture struct {
+	vrp     *vrp.VehicleRoutingProblem
+	route   *route.VehicleRoute
+	pickup  *job.Pickup
+	deliver *job.Delivery
+	service *job.Service
+}
+
+// newFixture creates a route along the x-axis: start(0) -> pickup(10) -> delivery(20) -> service(30) -> end(0).
+func newFixture() *fixture {
+	tw, _ := activity.NewTimeWindow(0., 40.)
+	pickup := job.NewPickupBuilder("pickup").SetLocation(problem.NewLocationWithCoordinate(10, 0)).
+		AddSizeDimension(0, 2).SetServiceTime(5.).Build()
+	deliver := job.NewDeliveryBuilder("delivery").SetLocation(problem.NewLocationWithCoordinate(20, 0)).
+		AddSizeDimension(0, 3).Build()
+	service := job.NewServiceBuilder[*job.Service]("service").SetLocation(problem.NewLocationWithCoordinate(30, 0)).
+		AddSizeDimension(0, 1).SetTimeWindow(tw).Build()
+	t := vehicle.NewVehicleTypeBuilder("type").AddCapacityDimension(0, 10).Build()
+	v := vehicle.NewVehicleBuilder("v").SetType(t).SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).
+		SetLatestArrival(100.).Build()
+	p := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(v).AddJob(pickup).AddJob(deliver).AddJob(service).Build()
+	r := route.NewVehicleRouteBuilder(v, driver.NewNoDriver()).
+		AddPickup(pickup).AddDelivery(deliver).AddService(service).Build()
+	return &fixture{vrp: p, route: r, pickup: pickup, deliver: deliver, service: service}
+}
+
+func TestStateManager_ShouldUpdateArrivalAndEndTimes(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	sm.UpdateRoute(f.route)
+
+	acts := f.route.Activities()
+	assert.Equal(t, 10., acts[0].ArrTime())
+	assert.Equal(t, 15., acts[0].EndTime())
+	assert.Equal(t, 25., acts[1].ArrTime())
+	assert.Equal(t, 35., acts[2].ArrTime())
+	assert.Equal(t, 65., f.route.End().ArrTime())
+}
+
+func TestStateManager_ShouldPropagateLatestOperationStartTimeBackward(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	sm.UpdateRoute(f.route)
+
+	acts := f.route.Activities()
+	// the service must start by 40 and the vehicle must be back by 100
+	assert.Equal(t, 40., sm.LatestOperationStartTime(acts[2]))
+	assert.Equal(t, 30., sm.LatestOperationStartTime(acts[1]))
+	assert.Equal(t, 15., sm.LatestOperationStartTime(acts[0]))
+}
+
+func TestStateManager_ShouldUpdateLoads(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	sm.UpdateRoute(f.route)
+
+	acts := f.route.Activities()
+	assert.Equal(t, 3, sm.LoadAtBeginning(f.route).Get(0))
+	assert.Equal(t, 5, sm.Load(acts[0]).Get(0))
+	assert.Equal(t, 2, sm.Load(acts[1]).Get(0))
+	assert.Equal(t, 3, sm.Load(acts[2]).Get(0))
+	assert.Equal(t, 3, sm.LoadAtEnd(f.route).Get(0))
+	assert.Equal(t, 5, sm.MaxLoad(f.route).Get(0))
+
+	assert.Equal(t, 5, sm.PastMaxLoad(acts[1]).Get(0))
+	assert.Equal(t, 3, sm.FutureMaxLoad(acts[1]).Get(0))
+	assert.Equal(t, 5, sm.FutureMaxLoad(acts[0]).Get(0))
+}
+
+func TestStateManager_ShouldUpdateRouteWhenJobIsRemoved(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	sm.RuinStarts([]*route.VehicleRoute{f.route})
+
+	f.route.TourActivities().RemoveJob(f.pickup)
+	sm.Removed(f.pickup, f.route)
+
+	acts := f.route.Activities()
+	assert.Equal(t, 20., acts[0].ArrTime())
+	assert.Equal(t, 3, sm.MaxLoad(f.route).Get(0))
+}
+
+func TestStateManager_RemoveRoute_ShouldRemoveStatesOfRouteAndActivities(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	sm.UpdateRoute(f.route)
+
+	sm.RemoveRoute(f.route)
+
+	_, ok := sm.RouteState(f.route, InternalStates.MaxLoad)
+	assert.False(t, ok)
+	for _, act := range f.route.Activities() {
+		_, ok := sm.ActivityState(act, InternalStates.Load)
+		assert.False(t, ok)
+	}
+}
+
+func TestStateManager_ShouldStoreCustomStates(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	id := sm.CreateStateId("distance")
+	assert.Equal(t, id, sm.CreateStateId("distance"))
+	assert.Panics(t, func() { sm.CreateStateId(InternalStates.Load.Name()) })
+	assert.Panics(t, func() { sm.PutRouteState(f.route, InternalStates.MaxLoad, problem.NewCapacity(nil)) })
+
+	act := f.route.Activities()[0]
+	sm.PutActivityState(act, id, 42.)
+	sm.PutRouteState(f.route, id, 60.)
+	actState, ok := sm.ActivityState(act, id)
+	assert.True(t, ok)
+	assert.Equal(t, 42., actState)
+	routeState, ok := sm.RouteState(f.route, id)
+	assert.True(t, ok)
+	assert.Equal(t, 60., routeState)
+	_, ok = sm.ActivityState(f.route.Activities()[1], id)
+	assert.False(t, ok)
+}
+
+type distanceUpdater struct {
+	sm           *StateManager
+	id           StateId
+	vehicleRoute *route.VehicleRoute
+	prev         *problem.Location
+	distance     float64
+}
+
+func (u *distanceUpdater) Begin(vehicleRoute *route.VehicleRoute) {
+	u.vehicleRoute, u.prev, u.distance = vehicleRoute, vehicleRoute.Start().Location(), 0.
+}
+
+func (u *distanceUpdater) Visit(act problem.TourActivity) {
+	u.distance += act.Location().Coordinate().X - u.prev.Coordinate().X
+	u.prev = act.Location()
+}
+
+func (u *distanceUpdater) Finish() {
+	u.sm.PutRouteState(u.vehicleRoute, u.id, u.distance)
+}
+
+func TestStateManager_CustomUpdatersMustBeRunOnUpdate(t *testing.T) {
+	f := newFixture()
+	sm := NewStateManager(f.vrp)
+	u := &distanceUpdater{sm: sm, id: sm.CreateStateId("distance")}
+	sm.AddActivityVisitor(u)
+
+	sm.InformInsertionStarts([]*route.VehicleRoute{f.route}, nil)
+
+	distance, ok := sm.RouteState(f.route, u.id)
+	assert.True(t, ok)
+	assert.Equal(t, 30., distance)
+}
@@ -0,0 +1,25 @@
+package state
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+)
+
+// ActivityVisitor visits the activities of a route from its start to its end.
+type ActivityVisitor interface {
+	Begin(vehicleRoute *route.VehicleRoute)
+	Visit(act problem.TourActivity)
+	Finish()
+}
+
+// ReverseActivityVisitor visits the activities of a route from its end to its start.
+type ReverseActivityVisitor interface {
+	Begin(vehicleRoute *route.VehicleRoute)
+	Visit(act problem.TourActivity)
+	Finish()
+}
+
+// RouteVisitor visits a route as a whole, after all activity visitors are done.
+type RouteVisitor interface {
+	VisitRoute(vehicleRoute *route.VehicleRoute)
+}
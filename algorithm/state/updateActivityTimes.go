@@ -0,0 +1,51 @@
+package state
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/solution/route"
+	"math"
+)
+
+var _ ActivityVisitor = (*UpdateActivityTimes)(nil)
+
+// UpdateActivityTimes sets the arrival and end times of the activities of a route, including its end.
+type UpdateActivityTimes struct {
+	transportCosts cost.VehicleRoutingTransportCosts
+	activityCosts  cost.VehicleRoutingActivityCosts
+	vehicleRoute   *route.VehicleRoute
+	prevLocation   *problem.Location
+	prevEndTime    float64
+}
+
+func NewUpdateActivityTimes(transportCosts cost.VehicleRoutingTransportCosts, activityCosts cost.VehicleRoutingActivityCosts) *UpdateActivityTimes {
+	return &UpdateActivityTimes{
+		transportCosts: transportCosts,
+		activityCosts:  activityCosts,
+	}
+}
+
+func (u *UpdateActivityTimes) Begin(vehicleRoute *route.VehicleRoute) {
+	u.vehicleRoute = vehicleRoute
+	u.prevLocation = vehicleRoute.Start().Location()
+	u.prevEndTime = vehicleRoute.Start().EndTime()
+}
+
+func (u *UpdateActivityTimes) Visit(act problem.TourActivity) {
+	v, d := u.vehicleRoute.Vehicle(), u.vehicleRoute.Driver()
+	arrTime := u.prevEndTime + u.transportCosts.TransportTime(u.prevLocation, act.Location(), u.prevEndTime, d, v)
+	operationStart := math.Max(arrTime, act.TheoreticalEarliestOperationStartTime())
+	act.SetArrTime(arrTime)
+	act.SetEndTime(operationStart + u.activityCosts.ActivityDuration(act, arrTime, d, v))
+	u.prevLocation, u.prevEndTime = act.Location(), act.EndTime()
+}
+
+func (u *UpdateActivityTimes) Finish() {
+	end := u.vehicleRoute.End()
+	arrTime := u.prevEndTime
+	if u.vehicleRoute.Vehicle().IsReturnToDepot() {
+		arrTime += u.transportCosts.TransportTime(u.prevLocation, end.Location(), u.prevEndTime, u.vehicleRoute.Driver(), u.vehicleRoute.Vehicle())
+	}
+	end.SetArrTime(arrTime)
+	end.SetEndTime(arrTime)
+}
@@ -0,0 +1,53 @@
+package state
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/solution/route"
+	"math"
+)
+
+var _ ReverseActivityVisitor = (*UpdateLatestOperationStartTime)(nil)
+
+// UpdateLatestOperationStartTime propagates the latest arrival at the end of a route backward and stores for
+// every activity the latest time it can start without making one of its successors late.
+type UpdateLatestOperationStartTime struct {
+	stateManager        *StateManager
+	transportCosts      cost.VehicleRoutingTransportCosts
+	activityCosts       cost.VehicleRoutingActivityCosts
+	vehicleRoute        *route.VehicleRoute
+	nextLocation        *problem.Location
+	latestArrTimeAtNext float64
+	isLast              bool
+}
+
+func NewUpdateLatestOperationStartTime(stateManager *StateManager, transportCosts cost.VehicleRoutingTransportCosts,
+	activityCosts cost.VehicleRoutingActivityCosts) *UpdateLatestOperationStartTime {
+	return &UpdateLatestOperationStartTime{
+		stateManager:   stateManager,
+		transportCosts: transportCosts,
+		activityCosts:  activityCosts,
+	}
+}
+
+func (u *UpdateLatestOperationStartTime) Begin(vehicleRoute *route.VehicleRoute) {
+	u.vehicleRoute = vehicleRoute
+	u.nextLocation = vehicleRoute.End().Location()
+	u.latestArrTimeAtNext = vehicleRoute.Vehicle().LatestArrival()
+	u.isLast = true
+}
+
+func (u *UpdateLatestOperationStartTime) Visit(act problem.TourActivity) {
+	v, d := u.vehicleRoute.Vehicle(), u.vehicleRoute.Driver()
+	latestEndTime := u.latestArrTimeAtNext
+	// a vehicle not returning to its depot ends its route right after the last activity
+	if !u.isLast || v.IsReturnToDepot() {
+		latestEndTime -= u.transportCosts.BackwardTransportTime(act.Location(), u.nextLocation, u.latestArrTimeAtNext, d, v)
+	}
+	latestStart := math.Min(act.TheoreticalLatestOperationStartTime(),
+		latestEndTime-u.activityCosts.ActivityDuration(act, latestEndTime, d, v))
+	u.stateManager.putActivityState(act, InternalStates.LatestOperationStartTime, latestStart)
+	u.nextLocation, u.latestArrTimeAtNext, u.isLast = act.Location(), latestStart, false
+}
+
+func (u *UpdateLatestOperationStartTime) Finish() {}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"gsprit/problem/solution/route/activity"
+)
+
+var _ ActivityVisitor = (*UpdateLoads)(nil)
+
+// UpdateLoads stores the load after every activity of a route as well as the load at its beginning and end.
+// Deliveries of services are loaded at the depot, i.e. they make up the load at the beginning.
+type UpdateLoads struct {
+	stateManager *StateManager
+	vehicleRoute *route.VehicleRoute
+	currentLoad  *problem.Capacity
+}
+
+func NewUpdateLoads(stateManager *StateManager) *UpdateLoads {
+	return &UpdateLoads{
+		stateManager: stateManager,
+	}
+}
+
+func (u *UpdateLoads) Begin(vehicleRoute *route.VehicleRoute) {
+	u.vehicleRoute = vehicleRoute
+	loadAtDepot := problem.NewCapacity(nil)
+	for _, act := range vehicleRoute.Activities() {
+		if _, ok := act.(*activity.DeliverService); ok {
+			loadAtDepot = problem.AddUp(loadAtDepot, problem.Invert(act.Size()))
+		}
+	}
+	u.currentLoad = loadAtDepot
+	u.stateManager.putRouteState(vehicleRoute, InternalStates.LoadAtBeginning, loadAtDepot)
+}
+
+func (u *UpdateLoads) Visit(act problem.TourActivity) {
+	u.currentLoad = problem.AddUp(u.currentLoad, act.Size())
+	u.stateManager.putActivityState(act, InternalStates.Load, u.currentLoad)
+}
+
+func (u *UpdateLoads) Finish() {
+	u.stateManager.putRouteState(u.vehicleRoute, InternalStates.LoadAtEnd, u.currentLoad)
+}
+
+var _ ActivityVisitor = (*UpdatePastMaxLoad)(nil)
+
+// UpdatePastMaxLoad stores for every activity the maximum load from the beginning of the route up to the activity.
+// It requires the loads, i.e. it has to run after UpdateLoads.
+type UpdatePastMaxLoad struct {
+	stateManager *StateManager
+	maxLoad      *problem.Capacity
+}
+
+func NewUpdatePastMaxLoad(stateManager *StateManager) *UpdatePastMaxLoad {
+	return &UpdatePastMaxLoad{
+		stateManager: stateManager,
+	}
+}
+
+func (u *UpdatePastMaxLoad) Begin(vehicleRoute *route.VehicleRoute) {
+	u.maxLoad = u.stateManager.LoadAtBeginning(vehicleRoute)
+}
+
+func (u *UpdatePastMaxLoad) Visit(act problem.TourActivity) {
+	u.maxLoad = problem.Max(u.maxLoad, u.stateManager.Load(act))
+	u.stateManager.putActivityState(act, InternalStates.PastMaxLoad, u.maxLoad)
+}
+
+func (u *UpdatePastMaxLoad) Finish() {}
+
+var _ ReverseActivityVisitor = (*UpdateFutureMaxLoad)(nil)
+
+// UpdateFutureMaxLoad stores for every activity the maximum load from the activity to the end of the route, and
+// the maximum load of the whole route.
+type UpdateFutureMaxLoad struct {
+	stateManager *StateManager
+	vehicleRoute *route.VehicleRoute
+	maxLoad      *problem.Capacity
+}
+
+func NewUpdateFutureMaxLoad(stateManager *StateManager) *UpdateFutureMaxLoad {
+	return &UpdateFutureMaxLoad{
+		stateManager: stateManager,
+	}
+}
+
+func (u *UpdateFutureMaxLoad) Begin(vehicleRoute *route.VehicleRoute) {
+	u.vehicleRoute = vehicleRoute
+	u.maxLoad = u.stateManager.LoadAtEnd(vehicleRoute)
+}
+
+func (u *UpdateFutureMaxLoad) Visit(act problem.TourActivity) {
+	u.maxLoad = problem.Max(u.maxLoad, u.stateManager.Load(act))
+	u.stateManager.putActivityState(act, InternalStates.FutureMaxLoad, u.maxLoad)
+}
+
+func (u *UpdateFutureMaxLoad) Finish() {
+	maxLoad := problem.Max(u.maxLoad, u.stateManager.LoadAtBeginning(u.vehicleRoute))
+	u.stateManager.putRouteState(u.vehicleRoute, InternalStates.MaxLoad, maxLoad)
+}
@@ -0,0 +1,56 @@
+package termination
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var (
+	_ PrematureAlgorithmTermination     = (*IterationWithoutImprovementTermination)(nil)
+	_ algorithm.AlgorithmStartsListener = (*IterationWithoutImprovementTermination)(nil)
+)
+
+// IterationWithoutImprovementTermination stops the search if the given number of consecutive iterations has not
+// discovered a solution better than the best one known.
+type IterationWithoutImprovementTermination struct {
+	noIterationsWithoutImprovement int
+	iterationsWithoutImprovement   int
+	bestCosts                      float64
+}
+
+func NewIterationWithoutImprovementTermination(noIterationsWithoutImprovement int) *IterationWithoutImprovementTermination {
+	if noIterationsWithoutImprovement < 1 {
+		panic("number of iterations without improvement must be at least 1")
+	}
+	return &IterationWithoutImprovementTermination{
+		noIterationsWithoutImprovement: noIterationsWithoutImprovement,
+		bestCosts:                      math.MaxFloat64,
+	}
+}
+
+// InformAlgorithmStarts resets the counter; the best initial solution is the one to improve.
+func (t *IterationWithoutImprovementTermination) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm algorithm.VehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	t.iterationsWithoutImprovement = 0
+	t.bestCosts = math.MaxFloat64
+	if len(solutions) > 0 {
+		t.bestCosts = solution.BestOf(solutions).Cost()
+	}
+}
+
+func (t *IterationWithoutImprovementTermination) IsPrematureBreak(discoveredSolution *algorithm.DiscoveredSolution) bool {
+	if costs := discoveredSolution.Solution().Cost(); costs < t.bestCosts {
+		t.bestCosts = costs
+		t.iterationsWithoutImprovement = 0
+	} else {
+		t.iterationsWithoutImprovement++
+	}
+	return t.iterationsWithoutImprovement >= t.noIterationsWithoutImprovement
+}
+
+func (t *IterationWithoutImprovementTermination) String() string {
+	return fmt.Sprintf("[name=iterationWithoutImprovementTermination][noIterations=%d]", t.noIterationsWithoutImprovement)
+}
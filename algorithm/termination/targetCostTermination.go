@@ -0,0 +1,27 @@
+package termination
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+)
+
+var _ PrematureAlgorithmTermination = (*TargetCostTermination)(nil)
+
+// TargetCostTermination stops the search as soon as a solution with costs not exceeding the target is discovered.
+type TargetCostTermination struct {
+	targetCosts float64
+}
+
+func NewTargetCostTermination(targetCosts float64) *TargetCostTermination {
+	return &TargetCostTermination{
+		targetCosts: targetCosts,
+	}
+}
+
+func (t *TargetCostTermination) IsPrematureBreak(discoveredSolution *algorithm.DiscoveredSolution) bool {
+	return discoveredSolution.Solution().Cost() <= t.targetCosts
+}
+
+func (t *TargetCostTermination) String() string {
+	return fmt.Sprintf("[name=targetCostTermination][targetCosts=%.2f]", t.targetCosts)
+}
@@ -0,0 +1,56 @@
+package termination
+
+import (
+	"testing"
+	"time"
+
+	"gsprit/algorithm"
+	"gsprit/problem/solution"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func discovered(cost float64) *algorithm.DiscoveredSolution {
+	return algorithm.NewDiscoveredSolution(solution.NewVehicleRoutingProblemSolution(nil, cost), true, "strategy")
+}
+
+func TestTimeTermination_ShouldBreakAfterMaxTime(t *testing.T) {
+	now := time.Unix(0, 0)
+	tt := NewTimeTermination(10 * time.Second)
+	tt.now = func() time.Time { return now }
+	tt.InformAlgorithmStarts(nil, nil, nil)
+
+	now = now.Add(9 * time.Second)
+	assert.False(t, tt.IsPrematureBreak(discovered(10.)))
+	now = now.Add(time.Second)
+	assert.True(t, tt.IsPrematureBreak(discovered(10.)))
+}
+
+func TestIterationWithoutImprovementTermination_ShouldCountConsecutiveIterations(t *testing.T) {
+	tt := NewIterationWithoutImprovementTermination(2)
+	tt.InformAlgorithmStarts(nil, nil, []*solution.VehicleRoutingProblemSolution{solution.NewVehicleRoutingProblemSolution(nil, 100.)})
+
+	assert.False(t, tt.IsPrematureBreak(discovered(110.)))
+	assert.False(t, tt.IsPrematureBreak(discovered(90.)))
+	assert.False(t, tt.IsPrematureBreak(discovered(95.)))
+	assert.True(t, tt.IsPrematureBreak(discovered(90.)))
+}
+
+func TestVariationCoefficientTermination_ShouldBreakOnceBestCostsConverge(t *testing.T) {
+	tt := NewVariationCoefficientTermination(3, .01)
+
+	assert.False(t, tt.IsPrematureBreak(discovered(200.)))
+	assert.False(t, tt.IsPrematureBreak(discovered(100.)))
+	// window [200 100 100] varies too much
+	assert.False(t, tt.IsPrematureBreak(discovered(150.)))
+	// window [100 100 100], worse solutions do not change the best costs
+	assert.True(t, tt.IsPrematureBreak(discovered(120.)))
+	assert.Equal(t, 0., tt.VariationCoefficient())
+}
+
+func TestTargetCostTermination_ShouldBreakWhenTargetIsReached(t *testing.T) {
+	tt := NewTargetCostTermination(100.)
+
+	assert.False(t, tt.IsPrematureBreak(discovered(100.1)))
+	assert.True(t, tt.IsPrematureBreak(discovered(100.)))
+}
@@ -0,0 +1,46 @@
+package termination
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"time"
+)
+
+var (
+	_ PrematureAlgorithmTermination     = (*TimeTermination)(nil)
+	_ algorithm.AlgorithmStartsListener = (*TimeTermination)(nil)
+)
+
+// TimeTermination stops the search once the given wall-clock time has passed since the algorithm started. The
+// time is checked after each iteration, i.e. the last iteration may exceed the limit.
+type TimeTermination struct {
+	maxTime time.Duration
+	start   time.Time
+	now     func() time.Time
+}
+
+func NewTimeTermination(maxTime time.Duration) *TimeTermination {
+	return &TimeTermination{
+		maxTime: maxTime,
+		now:     time.Now,
+	}
+}
+
+func (t *TimeTermination) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm algorithm.VehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	t.start = t.now()
+}
+
+func (t *TimeTermination) IsPrematureBreak(discoveredSolution *algorithm.DiscoveredSolution) bool {
+	if t.start.IsZero() {
+		// not registered as listener, the time runs from the first iteration
+		t.start = t.now()
+	}
+	return t.now().Sub(t.start) >= t.maxTime
+}
+
+func (t *TimeTermination) String() string {
+	return fmt.Sprintf("[name=timeTermination][maxTime=%v]", t.maxTime)
+}
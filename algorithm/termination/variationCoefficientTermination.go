@@ -0,0 +1,91 @@
+package termination
+
+import (
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/problem/solution"
+	"gsprit/problem/vrp"
+	"math"
+)
+
+var (
+	_ PrematureAlgorithmTermination     = (*VariationCoefficientTermination)(nil)
+	_ algorithm.AlgorithmStartsListener = (*VariationCoefficientTermination)(nil)
+)
+
+// VariationCoefficientTermination stops the search once the best costs have converged, i.e. once the coefficient
+// of variation (standard deviation divided by mean) of the best costs of the last windowSize iterations falls
+// below the threshold.
+type VariationCoefficientTermination struct {
+	windowSize int
+	threshold  float64
+	bestCosts  float64
+	window     []float64
+	next       int
+	full       bool
+}
+
+func NewVariationCoefficientTermination(windowSize int, threshold float64) *VariationCoefficientTermination {
+	if windowSize < 2 {
+		panic("window size must be at least 2")
+	}
+	return &VariationCoefficientTermination{
+		windowSize: windowSize,
+		threshold:  threshold,
+		bestCosts:  math.MaxFloat64,
+		window:     make([]float64, windowSize),
+	}
+}
+
+// InformAlgorithmStarts clears the window.
+func (t *VariationCoefficientTermination) InformAlgorithmStarts(problem *vrp.VehicleRoutingProblem, algorithm algorithm.VehicleRoutingAlgorithm,
+	solutions []*solution.VehicleRoutingProblemSolution) {
+	t.next, t.full = 0, false
+	t.bestCosts = math.MaxFloat64
+	if len(solutions) > 0 {
+		t.bestCosts = solution.BestOf(solutions).Cost()
+	}
+}
+
+func (t *VariationCoefficientTermination) IsPrematureBreak(discoveredSolution *algorithm.DiscoveredSolution) bool {
+	t.bestCosts = math.Min(t.bestCosts, discoveredSolution.Solution().Cost())
+	t.window[t.next] = t.bestCosts
+	t.next = (t.next + 1) % t.windowSize
+	if t.next == 0 {
+		t.full = true
+	}
+	if !t.full {
+		return false
+	}
+	return t.VariationCoefficient() < t.threshold
+}
+
+// VariationCoefficient returns the coefficient of variation of the best costs in the window. It is zero if all
+// costs are zero.
+func (t *VariationCoefficientTermination) VariationCoefficient() float64 {
+	n := t.windowSize
+	if !t.full {
+		n = t.next
+	}
+	if n == 0 {
+		return 0.
+	}
+	mean := 0.
+	for _, c := range t.window[:n] {
+		mean += c
+	}
+	mean /= float64(n)
+	if mean == 0. {
+		return 0.
+	}
+	variance := 0.
+	for _, c := range t.window[:n] {
+		variance += (c - mean) * (c - mean)
+	}
+	variance /= float64(n)
+	return math.Sqrt(variance) / math.Abs(mean)
+}
+
+func (t *VariationCoefficientTermination) String() string {
+	return fmt.Sprintf("[name=variationCoefficientTermination][windowSize=%d][threshold=%.4f]", t.windowSize, t.threshold)
+}
@@ -0,0 +1,96 @@
+package vra
+
+import (
+	"gsprit/algorithm"
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/ruin"
+	"gsprit/problem"
+	"gsprit/problem/solution/route"
+	"slices"
+)
+
+var (
+	_ ruin.RuinListener                = (*moduleEventRecorder)(nil)
+	_ recreate.InsertionStartsListener = (*moduleEventRecorder)(nil)
+	_ recreate.JobInsertedListener     = (*moduleEventRecorder)(nil)
+	_ recreate.VehicleSwitchedListener = (*moduleEventRecorder)(nil)
+	_ recreate.InsertionEndsListener   = (*moduleEventRecorder)(nil)
+)
+
+// moduleEvent informs a module listener about an event if it listens to events of that kind.
+type moduleEvent func(l algorithm.SearchStrategyModuleListener)
+
+// moduleEventRecorder records the ruin and insertion events of the modules of a worker of the parallel search,
+// such that they can be replayed to the algorithm's module listeners on the coordinating goroutine, one iteration
+// after another. Slices are copied when an event is recorded, routes are not: a replayed event refers to the
+// routes as they are at the end of the iteration.
+type moduleEventRecorder struct {
+	events []moduleEvent
+}
+
+// takeEvents returns the events recorded since the last call.
+func (r *moduleEventRecorder) takeEvents() []moduleEvent {
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func (r *moduleEventRecorder) RuinStarts(routes []*route.VehicleRoute) {
+	routes = slices.Clone(routes)
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if rl, ok := l.(ruin.RuinListener); ok {
+			rl.RuinStarts(routes)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) RuinEnds(routes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	routes, unassignedJobs = slices.Clone(routes), slices.Clone(unassignedJobs)
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if rl, ok := l.(ruin.RuinListener); ok {
+			rl.RuinEnds(routes, unassignedJobs)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) Removed(job problem.Job, fromRoute *route.VehicleRoute) {
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if rl, ok := l.(ruin.RuinListener); ok {
+			rl.Removed(job, fromRoute)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) InformInsertionStarts(vehicleRoutes []*route.VehicleRoute, unassignedJobs []problem.Job) {
+	vehicleRoutes, unassignedJobs = slices.Clone(vehicleRoutes), slices.Clone(unassignedJobs)
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if il, ok := l.(recreate.InsertionStartsListener); ok {
+			il.InformInsertionStarts(vehicleRoutes, unassignedJobs)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) InformJobInserted(job problem.Job, inRoute *route.VehicleRoute, additionalCosts float64) {
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if il, ok := l.(recreate.JobInsertedListener); ok {
+			il.InformJobInserted(job, inRoute, additionalCosts)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) InformVehicleSwitched(vehicleRoute *route.VehicleRoute, oldVehicle, newVehicle problem.Vehicle) {
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if il, ok := l.(recreate.VehicleSwitchedListener); ok {
+			il.InformVehicleSwitched(vehicleRoute, oldVehicle, newVehicle)
+		}
+	})
+}
+
+func (r *moduleEventRecorder) InformInsertionEnds(vehicleRoutes []*route.VehicleRoute, badJobs []problem.Job) {
+	vehicleRoutes, badJobs = slices.Clone(vehicleRoutes), slices.Clone(badJobs)
+	r.events = append(r.events, func(l algorithm.SearchStrategyModuleListener) {
+		if il, ok := l.(recreate.InsertionEndsListener); ok {
+			il.InformInsertionEnds(vehicleRoutes, badJobs)
+		}
+	})
+}
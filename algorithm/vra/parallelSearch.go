@@ -0,0 +1,190 @@
+package vra
+
+import (
+	"context"
+	"fmt"
+	"gsprit/algorithm"
+	"gsprit/problem/solution"
+	"gsprit/util"
+	"log"
+	"math/rand/v2"
+	"sync"
+)
+
+// SearchStrategyManagerFactory creates the search strategies of a worker of the parallel search. The manager
+// returned has to contain a strategy for every strategy id of the algorithm's SearchStrategyManager. Since workers
+// run their strategies concurrently, the modules of the strategies must not be shared with other workers. random
+// is the generator of the worker, to be injected into its modules.
+type SearchStrategyManagerFactory func(worker int, random *rand.Rand) (*algorithm.SearchStrategyManager, error)
+
+// parallelTask carries the copy of the selected solution a worker improves. selected itself belongs to the pool
+// and is only passed through, workers never touch it.
+type parallelTask struct {
+	strategyId string
+	selected   *solution.VehicleRoutingProblemSolution
+	solution   *solution.VehicleRoutingProblemSolution
+}
+
+// parallelResult carries the solution a worker has derived from selected and the module events of its iteration.
+type parallelResult struct {
+	strategyId string
+	selected   *solution.VehicleRoutingProblemSolution
+	solution   *solution.VehicleRoutingProblemSolution
+	events     []moduleEvent
+}
+
+// newWorkerStrategies creates the strategy managers of the workers. The algorithm's module listeners are not
+// registered with the modules of the workers, since these run concurrently. Instead, if there are module
+// listeners, every worker gets a recorder of its module events, which are replayed to the listeners on the
+// coordinating goroutine.
+func (a *VehicleRoutingAlgorithm) newWorkerStrategies() ([]*algorithm.SearchStrategyManager, []*moduleEventRecorder, error) {
+	if a.searchStrategyManagerFactory == nil {
+		return nil, nil, fmt.Errorf("searching with %d threads requires a search strategy manager factory", a.numberOfThreads)
+	}
+	managers := make([]*algorithm.SearchStrategyManager, a.numberOfThreads)
+	recorders := make([]*moduleEventRecorder, a.numberOfThreads)
+	for w := range managers {
+		m, err := a.searchStrategyManagerFactory(w, util.NewRandomStream(a.seed, uint64(w)))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, strategy := range a.searchStrategyManager.Strategies() {
+			if _, ok := m.Strategy(strategy.Id()); !ok {
+				return nil, nil, fmt.Errorf("strategies of worker %d miss strategy %s", w, strategy.Id())
+			}
+		}
+		if len(a.moduleListeners()) > 0 {
+			recorders[w] = &moduleEventRecorder{}
+			m.AddSearchStrategyModuleListener(recorders[w])
+		}
+		managers[w] = m
+	}
+	return managers, recorders, nil
+}
+
+func (a *VehicleRoutingAlgorithm) moduleListeners() []algorithm.SearchStrategyModuleListener {
+	listeners := make([]algorithm.SearchStrategyModuleListener, 0)
+	for _, l := range a.algoListeners.AlgorithmListeners() {
+		if ssml, ok := l.(algorithm.SearchStrategyModuleListener); ok {
+			listeners = append(listeners, ssml)
+		}
+	}
+	return listeners
+}
+
+// replayModuleEvents informs the module listeners about the events of an iteration of a worker.
+func (a *VehicleRoutingAlgorithm) replayModuleEvents(events []moduleEvent) {
+	if len(events) == 0 {
+		return
+	}
+	listeners := a.moduleListeners()
+	for _, event := range events {
+		for _, l := range listeners {
+			event(l)
+		}
+	}
+}
+
+// searchInParallel runs the iterations on numberOfThreads workers. Strategy selection, solution selection and
+// acceptance happen on the calling goroutine, which owns the solution pool; workers apply the modules of their own
+// strategies to copies of the selected solutions. Results are processed one worker after another, each one as an
+// iteration of its own, and a worker gets its next task as soon as its result is accepted or rejected. Hence
+// listeners, including the module listeners, are informed one iteration after another and, since the order of the
+// iterations does not depend on the scheduling of the workers, runs with the same seed discover the same solutions.
+//
+// It returns the number of iterations run and an error if the search failed or, wrapping ctx.Err(), if ctx has
+// stopped it.
+func (a *VehicleRoutingAlgorithm) searchInParallel(ctx context.Context, solutions *[]*solution.VehicleRoutingProblemSolution) (int, error) {
+	managers, recorders, err := a.newWorkerStrategies()
+	if err != nil {
+		return 0, err
+	}
+	tasks := make([]chan parallelTask, len(managers))
+	results := make([]chan parallelResult, len(managers))
+	var wg sync.WaitGroup
+	for w, m := range managers {
+		tasks[w] = make(chan parallelTask, 1)
+		results[w] = make(chan parallelResult, 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks[w] {
+				strategy, _ := m.Strategy(task.strategyId)
+				result := parallelResult{strategyId: task.strategyId, selected: task.selected, solution: strategy.Improve(task.solution)}
+				if recorders[w] != nil {
+					result.events = recorders[w].takeEvents()
+				}
+				results[w] <- result
+			}
+		}()
+	}
+	defer func() {
+		for _, t := range tasks {
+			close(t)
+		}
+		wg.Wait()
+	}()
+
+	dispatched, inFlight := 0, 0
+	busy := make([]bool, len(managers))
+	dispatch := func(worker int) error {
+		strategy, err := a.searchStrategyManager.RandomStrategy()
+		if err != nil {
+			return err
+		}
+		selected, err := strategy.Select(*solutions)
+		if err != nil {
+			return err
+		}
+		tasks[worker] <- parallelTask{strategyId: strategy.Id(), selected: selected, solution: selected.Copy()}
+		dispatched++
+		inFlight++
+		busy[worker] = true
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, a.stoppedByContext(0, err)
+	}
+	for w := 0; w < len(managers) && dispatched < a.maxIterations; w++ {
+		if err := dispatch(w); err != nil {
+			return 0, err
+		}
+	}
+
+	iterations := 0
+	stopped := false
+	var stopErr error
+	for w := 0; inFlight > 0; w = (w + 1) % len(managers) {
+		if !busy[w] {
+			continue
+		}
+		result := <-results[w]
+		busy[w] = false
+		inFlight--
+		if stopped {
+			// the search has terminated, the remaining results are discarded
+			continue
+		}
+		iterations++
+		a.iterationStarts(iterations, a.problem, *solutions)
+		log.Printf("start iteration: %d", iterations-1)
+		a.counter.IncCounter()
+		a.replayModuleEvents(result.events)
+		strategy, _ := a.searchStrategyManager.Strategy(result.strategyId)
+		if a.completeIteration(iterations, strategy.Accept(solutions, result.selected, result.solution), *solutions) {
+			stopped = true
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			stopped = true
+			stopErr = a.stoppedByContext(iterations, err)
+			continue
+		}
+		if dispatched < a.maxIterations {
+			if err := dispatch(w); err != nil {
+				return iterations, err
+			}
+		}
+	}
+	return iterations, stopErr
+}
@@ -1,26 +1,49 @@
 package vra
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gsprit/algorithm"
-
+	"gsprit/algorithm/constraint"
+	"gsprit/algorithm/construction"
+	"gsprit/algorithm/objective"
+	"gsprit/algorithm/recreate"
+	"gsprit/algorithm/state"
 	"gsprit/algorithm/termination"
 	"gsprit/problem"
 	"gsprit/problem/solution"
 	"gsprit/problem/vrp"
+	"gsprit/util"
 	"log"
 	"maps"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 )
 
+// TerminationComposition defines how a TerminationManager combines its criteria.
+type TerminationComposition int
+
+const (
+	// AnyOf stops the search as soon as one criterion is met.
+	AnyOf TerminationComposition = iota
+	// AllOf stops the search once all criteria are met at the same time.
+	AllOf
+)
+
+// TerminationManager decides whether the search stops prematurely. All criteria are evaluated after every
+// iteration, hence criteria tracking the progress of the search stay up to date.
 type TerminationManager struct {
 	terminationCriteria []termination.PrematureAlgorithmTermination
+	composition         TerminationComposition
 }
 
 func newTerminationManager() *TerminationManager {
 	return &TerminationManager{
 		terminationCriteria: make([]termination.PrematureAlgorithmTermination, 0),
+		composition:         AnyOf,
 	}
 }
 
@@ -28,13 +51,28 @@ func (m *TerminationManager) AddTermination(termination termination.PrematureAlg
 	m.terminationCriteria = append(m.terminationCriteria, termination)
 }
 
+func (m *TerminationManager) SetComposition(composition TerminationComposition) {
+	m.composition = composition
+}
+
+func (m *TerminationManager) Composition() TerminationComposition {
+	return m.composition
+}
+
 func (m *TerminationManager) IsPrematureBreak(discoveredSolution *algorithm.DiscoveredSolution) bool {
+	if len(m.terminationCriteria) == 0 {
+		return false
+	}
+	nOfBreaks := 0
 	for _, termination := range m.terminationCriteria {
 		if termination.IsPrematureBreak(discoveredSolution) {
-			return true
+			nOfBreaks++
 		}
 	}
-	return false
+	if m.composition == AllOf {
+		return nOfBreaks == len(m.terminationCriteria)
+	}
+	return nOfBreaks > 0
 }
 
 type Counter struct {
@@ -71,24 +109,33 @@ func (c *Counter) Reset() {
 }
 
 type VehicleRoutingAlgorithm struct {
-	counter               *Counter
-	problem               *vrp.VehicleRoutingProblem
-	searchStrategyManager *algorithm.SearchStrategyManager
-	algoListeners         *VehicleRoutingAlgorithmListeners
-	initialSolutions      []*solution.VehicleRoutingProblemSolution
-	maxIterations         int
-	terminationManager    *TerminationManager
-	bestEver              *solution.VehicleRoutingProblemSolution
-	objectiveFunction     solution.SolutionCostCalculator
+	counter                      *Counter
+	problem                      *vrp.VehicleRoutingProblem
+	searchStrategyManager        *algorithm.SearchStrategyManager
+	searchStrategyManagerFactory SearchStrategyManagerFactory
+	algoListeners                *VehicleRoutingAlgorithmListeners
+	initialSolutions             []*solution.VehicleRoutingProblemSolution
+	initialSolutionFactory       algorithm.InitialSolutionFactory
+	maxIterations                int
+	numberOfThreads              int
+	seed                         uint64
+	terminationManager           *TerminationManager
+	bestEver                     *solution.VehicleRoutingProblemSolution
+	objectiveFunction            solution.SolutionCostCalculator
 }
 
 func NewVehicleRoutingAlgorithm(problem *vrp.VehicleRoutingProblem, searchStrategyManager *algorithm.SearchStrategyManager) *VehicleRoutingAlgorithm {
 	return &VehicleRoutingAlgorithm{
 		counter:               newCounter("iterations "),
+		problem:               problem,
 		maxIterations:         100,
+		numberOfThreads:       1,
+		seed:                  util.DefaultSeed,
 		terminationManager:    newTerminationManager(),
+		algoListeners:         NewVehicleRoutingAlgorithmListeners(),
 		initialSolutions:      make([]*solution.VehicleRoutingProblemSolution, 0),
 		searchStrategyManager: searchStrategyManager,
+		objectiveFunction:     objective.NewDefaultObjectiveFunction(problem),
 	}
 }
 
@@ -125,65 +172,125 @@ func (a *VehicleRoutingAlgorithm) verifyAndAdaptSolution(solution *solution.Vehi
 			}
 		}
 	}
-	unassignedJobs := solution.UnassignedJobs()
-	for _, job := range jobsNotInSolution {
-		unassignedJobs = append(unassignedJobs, job)
-	}
+	// the jobs are ordered by index, since their order affects the insertion of unassigned jobs
+	missingJobs := slices.Collect(maps.Values(jobsNotInSolution))
+	sort.Slice(missingJobs, func(i, j int) bool {
+		return missingJobs[i].Index() < missingJobs[j].Index()
+	})
+	unassignedJobs := append(solution.UnassignedJobs(), missingJobs...)
 	solution.SetUnassignedJobs(unassignedJobs)
 	solution.SetCost(a.objectiveFunction.Costs(solution))
 	return nil
 }
 
+// SetPrematureAlgorithmTermination replaces all termination criteria by the given one.
 func (a *VehicleRoutingAlgorithm) SetPrematureAlgorithmTermination(prematureAlgorithmTermination termination.PrematureAlgorithmTermination) {
+	composition := a.terminationManager.Composition()
 	a.terminationManager = newTerminationManager()
-	a.terminationManager.AddTermination(prematureAlgorithmTermination)
+	a.terminationManager.SetComposition(composition)
+	a.AddTerminationCriterion(prematureAlgorithmTermination)
 }
 
+// AddTerminationCriterion adds a termination criterion. Criteria listening to algorithm events are added as listeners.
 func (a *VehicleRoutingAlgorithm) AddTerminationCriterion(terminationCriterion termination.PrematureAlgorithmTermination) {
 	a.terminationManager.AddTermination(terminationCriterion)
+	switch terminationCriterion.(type) {
+	case algorithm.AlgorithmStartsListener, algorithm.IterationStartsListener, algorithm.IterationEndsListener, algorithm.AlgorithmEndsListener:
+		a.AddListener(terminationCriterion)
+	}
+}
+
+func (a *VehicleRoutingAlgorithm) TerminationManager() *TerminationManager {
+	return a.terminationManager
 }
 
 func (a *VehicleRoutingAlgorithm) SearchStrategyManager() *algorithm.SearchStrategyManager {
 	return a.searchStrategyManager
 }
 
+// SearchSolutions runs the search until the maximum number of iterations or a termination criterion is reached.
 func (a *VehicleRoutingAlgorithm) SearchSolutions() ([]*solution.VehicleRoutingProblemSolution, error) {
+	return a.SearchSolutionsWithContext(context.Background())
+}
+
+// SearchSolutionsWithContext runs the search like SearchSolutions but also stops once ctx is done. The context is
+// checked before every iteration, i.e. the current iteration is completed. If the search has been stopped by ctx,
+// the solutions found so far, including the best solution ever found, are returned together with an error
+// wrapping ctx.Err().
+func (a *VehicleRoutingAlgorithm) SearchSolutionsWithContext(ctx context.Context) ([]*solution.VehicleRoutingProblemSolution, error) {
 	log.Printf("algorithm starts: [maxIterations=%d]", a.maxIterations)
 	now := time.Now().UnixMilli()
-	noIterationsThisAlgoIsRunning := a.maxIterations
 	a.counter.Reset()
 	solutions := append([]*solution.VehicleRoutingProblemSolution{}, a.initialSolutions...)
+	if len(solutions) == 0 {
+		if a.initialSolutionFactory == nil {
+			a.initialSolutionFactory = a.defaultInitialSolutionFactory()
+		}
+		initialSolution := a.initialSolutionFactory.CreateSolution(a.problem)
+		initialSolution.SetCost(a.objectiveFunction.Costs(initialSolution))
+		solutions = append(solutions, initialSolution)
+	}
 	a.algorithmStarts(a.problem, solutions)
 	a.bestEver = solution.BestOf(solutions)
 	a.logSolutions(solutions)
 	log.Printf("iterations start")
+	search := a.searchSequentially
+	if a.numberOfThreads > 1 {
+		search = a.searchInParallel
+	}
+	noIterationsThisAlgoIsRunning, err := search(ctx, &solutions)
+	if err != nil && !errors.Is(err, ctx.Err()) {
+		return nil, err
+	}
+	log.Printf("iterations end at %d iterations", noIterationsThisAlgoIsRunning)
+	solutions = a.addBestEver(solutions)
+	a.algorithmEnds(a.problem, solutions)
+	log.Printf("took %.2f seconds", (float64(time.Now().UnixMilli()-now) / 1000.0))
+	return solutions, err
+}
+
+// searchSequentially runs the iterations one after another. It returns the number of iterations run and an error
+// if the search failed or, wrapping ctx.Err(), if ctx has stopped it.
+func (a *VehicleRoutingAlgorithm) searchSequentially(ctx context.Context, solutions *[]*solution.VehicleRoutingProblemSolution) (int, error) {
 	for i := 0; i < a.maxIterations; i++ {
-		a.iterationStarts(i+1, a.problem, solutions)
+		if err := ctx.Err(); err != nil {
+			return i, a.stoppedByContext(i, err)
+		}
+		a.iterationStarts(i+1, a.problem, *solutions)
 		log.Printf("start iteration: %d", i)
 		a.counter.IncCounter()
 		strategy, err := a.searchStrategyManager.RandomStrategy()
 		if err != nil {
-			return nil, err
+			return i, err
 		}
-		discoveredSolution, err := strategy.Run(a.problem, solutions)
+		discoveredSolution, err := strategy.RunOnPool(a.problem, solutions)
 		if err != nil {
-			return nil, err
+			return i, err
 		}
-		a.logDiscoveredSolution(discoveredSolution)
-		a.memorizeIfBestEver(discoveredSolution)
-		a.selectedStrategy(discoveredSolution, a.problem, solutions)
-		if a.terminationManager.IsPrematureBreak(discoveredSolution) {
-			log.Printf("premature algorithm termination at iteration %d", (i + 1))
-			noIterationsThisAlgoIsRunning = (i + 1)
-			break
+		if a.completeIteration(i+1, discoveredSolution, *solutions) {
+			return i + 1, nil
 		}
-		a.iterationEnds(i+1, a.problem, solutions)
 	}
-	log.Printf("iterations end at %d iterations", noIterationsThisAlgoIsRunning)
-	solutions = a.addBestEver(solutions)
-	a.algorithmEnds(a.problem, solutions)
-	log.Printf("took %.2f seconds", (float64(time.Now().UnixMilli()-now) / 1000.0))
-	return solutions, nil
+	return a.maxIterations, nil
+}
+
+// completeIteration informs about the solution discovered in iteration i and reports whether the search terminates.
+func (a *VehicleRoutingAlgorithm) completeIteration(i int, discoveredSolution *algorithm.DiscoveredSolution,
+	solutions []*solution.VehicleRoutingProblemSolution) bool {
+	a.logDiscoveredSolution(discoveredSolution)
+	a.memorizeIfBestEver(discoveredSolution)
+	a.selectedStrategy(discoveredSolution, a.problem, solutions)
+	if a.terminationManager.IsPrematureBreak(discoveredSolution) {
+		log.Printf("premature algorithm termination at iteration %d", i)
+		return true
+	}
+	a.iterationEnds(i, a.problem, solutions)
+	return false
+}
+
+func (a *VehicleRoutingAlgorithm) stoppedByContext(iterations int, err error) error {
+	log.Printf("algorithm stopped by context at iteration %d", iterations)
+	return fmt.Errorf("search stopped after %d iterations: %w", iterations, err)
 }
 
 func (a *VehicleRoutingAlgorithm) addBestEver(solutions []*solution.VehicleRoutingProblemSolution) []*solution.VehicleRoutingProblemSolution {
@@ -232,6 +339,11 @@ func (a *VehicleRoutingAlgorithm) logDiscoveredSolution(discoveredSolution *algo
 	a.logSolution(discoveredSolution.Solution())
 }
 
+// BestEver returns the best solution found by the last search.
+func (a *VehicleRoutingAlgorithm) BestEver() *solution.VehicleRoutingProblemSolution {
+	return a.bestEver
+}
+
 func (a *VehicleRoutingAlgorithm) memorizeIfBestEver(discoveredSolution *algorithm.DiscoveredSolution) {
 	if discoveredSolution == nil {
 		return
@@ -286,6 +398,54 @@ func (a *VehicleRoutingAlgorithm) MaxIterations() int {
 	return a.maxIterations
 }
 
+// SetInitialSolutionFactory sets the factory creating the initial solution if none has been added.
+func (a *VehicleRoutingAlgorithm) SetInitialSolutionFactory(initialSolutionFactory algorithm.InitialSolutionFactory) {
+	a.initialSolutionFactory = initialSolutionFactory
+}
+
+// defaultInitialSolutionFactory inserts all jobs with best insertion respecting the default constraints.
+func (a *VehicleRoutingAlgorithm) defaultInitialSolutionFactory() algorithm.InitialSolutionFactory {
+	stateManager := state.NewStateManager(a.problem)
+	constraintManager := constraint.NewConstraintManager(a.problem, stateManager)
+	bestInsertion := recreate.NewBestInsertion(a.problem, stateManager, constraintManager)
+	bestInsertion.SetRandom(util.NewRandomSource(a.seed).Next())
+	return construction.NewInsertionInitialSolutionFactory(bestInsertion, a.objectiveFunction)
+}
+
+// SetNumberOfThreads sets the number of threads searching in parallel. More than one thread requires a
+// SearchStrategyManagerFactory.
+func (a *VehicleRoutingAlgorithm) SetNumberOfThreads(numberOfThreads int) {
+	if numberOfThreads < 1 {
+		panic("number of threads must be at least 1")
+	}
+	a.numberOfThreads = numberOfThreads
+}
+
+// SetSearchStrategyManagerFactory sets the factory creating the strategies of the workers of a parallel search.
+func (a *VehicleRoutingAlgorithm) SetSearchStrategyManagerFactory(factory SearchStrategyManagerFactory) {
+	a.searchStrategyManagerFactory = factory
+}
+
+// SetSeed sets the master seed the random number generators of the default initial solution factory and of the
+// workers of a parallel search are derived from.
+func (a *VehicleRoutingAlgorithm) SetSeed(seed uint64) {
+	a.seed = seed
+}
+
+func (a *VehicleRoutingAlgorithm) NumberOfThreads() int {
+	return a.numberOfThreads
+}
+
+func (a *VehicleRoutingAlgorithm) Problem() *vrp.VehicleRoutingProblem {
+	return a.problem
+}
+
+// SetObjectiveFunction replaces the default objective function used to calculate the costs of initial solutions.
+// Search strategies calculate the costs of the solutions they discover with their own calculator.
+func (a *VehicleRoutingAlgorithm) SetObjectiveFunction(objectiveFunction solution.SolutionCostCalculator) {
+	a.objectiveFunction = objectiveFunction
+}
+
 func (a *VehicleRoutingAlgorithm) ObjectiveFunction() solution.SolutionCostCalculator {
 	return a.objectiveFunction
 }
@@ -0,0 +1,68 @@
+package vra
+
+import (
+	"fmt"
+	"testing"
+
+	"gsprit/algorithm"
+	"gsprit/algorithm/termination"
+	"gsprit/problem"
+	"gsprit/problem/cost"
+	"gsprit/problem/job"
+	"gsprit/problem/solution"
+	"gsprit/problem/vehicle"
+	"gsprit/problem/vrp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func discovered(cost float64) *algorithm.DiscoveredSolution {
+	return algorithm.NewDiscoveredSolution(solution.NewVehicleRoutingProblemSolution(nil, cost), true, "strategy")
+}
+
+func TestTerminationManager_ShouldComposeCriteria(t *testing.T) {
+	newManager := func(composition TerminationComposition) *TerminationManager {
+		m := newTerminationManager()
+		m.SetComposition(composition)
+		m.AddTermination(termination.NewTargetCostTermination(100.))
+		m.AddTermination(termination.NewIterationWithoutImprovementTermination(2))
+		return m
+	}
+
+	anyOf := newManager(AnyOf)
+	assert.False(t, anyOf.IsPrematureBreak(discovered(110.)))
+	assert.True(t, anyOf.IsPrematureBreak(discovered(90.)))
+
+	allOf := newManager(AllOf)
+	assert.False(t, allOf.IsPrematureBreak(discovered(90.)))
+	assert.False(t, allOf.IsPrematureBreak(discovered(95.)))
+	assert.True(t, allOf.IsPrematureBreak(discovered(95.)))
+}
+
+func TestTerminationManager_WithoutCriteriaShouldNeverBreak(t *testing.T) {
+	for _, composition := range []TerminationComposition{AnyOf, AllOf} {
+		m := newTerminationManager()
+		m.SetComposition(composition)
+		assert.False(t, m.IsPrematureBreak(discovered(0.)))
+	}
+}
+
+func TestAddInitialSolution_MissingJobsShouldBeUnassignedInOrderOfIndex(t *testing.T) {
+	b := vrp.NewBuilder().SetRoutingCost(cost.NewEuclideanCosts()).AddVehicle(vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithCoordinate(0, 0)).Build())
+	for i := 0; i < 20; i++ {
+		b.AddJob(job.NewServiceBuilder[*job.Service](fmt.Sprintf("s%02d", i)).
+			SetLocation(problem.NewLocationWithCoordinate(float64(i), 0)).Build())
+	}
+	alg := NewVehicleRoutingAlgorithm(b.Build(), algorithm.NewSearchStrategyManager())
+
+	for range 5 {
+		assert.NoError(t, alg.AddInitialSolution(solution.NewVehicleRoutingProblemSolution(nil, 0.)))
+	}
+
+	for _, s := range alg.initialSolutions {
+		assert.Len(t, s.UnassignedJobs(), 20)
+		for i, j := range s.UnassignedJobs() {
+			assert.Equal(t, i+1, j.Index())
+		}
+	}
+}
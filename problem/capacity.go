@@ -90,6 +90,47 @@ func Invert(cap *Capacity) *Capacity {
 	return NewCapacity(newDims)
 }
 
+// Max returns the maximum of two capacities per dimension
+func Max(cap1, cap2 *Capacity) *Capacity {
+	if cap1 == nil || cap2 == nil {
+		panic("arguments must not be null")
+	}
+	maxLen := max(len(cap1.dimensions), len(cap2.dimensions))
+	newDims := make([]int, maxLen)
+	for i := 0; i < maxLen; i++ {
+		newDims[i] = max(cap1.Get(i), cap2.Get(i))
+	}
+	return NewCapacity(newDims)
+}
+
+// IsLessOrEqual checks whether every dimension of c is less than or equal to the corresponding dimension of toCompare
+func (c *Capacity) IsLessOrEqual(toCompare *Capacity) bool {
+	if toCompare == nil {
+		panic("arguments must not be null")
+	}
+	maxLen := max(len(c.dimensions), len(toCompare.dimensions))
+	for i := 0; i < maxLen; i++ {
+		if c.Get(i) > toCompare.Get(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsGreaterOrEqual checks whether every dimension of c is greater than or equal to the corresponding dimension of toCompare
+func (c *Capacity) IsGreaterOrEqual(toCompare *Capacity) bool {
+	if toCompare == nil {
+		panic("arguments must not be null")
+	}
+	maxLen := max(len(c.dimensions), len(toCompare.dimensions))
+	for i := 0; i < maxLen; i++ {
+		if c.Get(i) < toCompare.Get(i) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Capacity) AddDimension(index, dimValue int) {
 	if index < len(c.dimensions) {
 		c.dimensions[index] = dimValue
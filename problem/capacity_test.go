@@ -164,3 +164,23 @@ func TestDifferentCapacities_ShouldReturnFalse(t *testing.T) {
 	cap2 := NewCapacityBuilder().AddDimension(0, 10).AddDimension(2, 1000).AddDimension(1, 100).Build()
 	assert.Equal(t, cap1.String(), cap2.String()) // Should be true as same values
 }
+
+func TestMaxOfTwoCapacities_ShouldReturnMaxPerDimension(t *testing.T) {
+	cap1 := NewCapacityBuilder().AddDimension(0, 3).AddDimension(1, 3).Build()
+	cap2 := NewCapacityBuilder().AddDimension(0, 2).AddDimension(1, 4).AddDimension(2, 1).Build()
+	maxCap := Max(cap1, cap2)
+	assert.Equal(t, 3, maxCap.Get(0))
+	assert.Equal(t, 4, maxCap.Get(1))
+	assert.Equal(t, 1, maxCap.Get(2))
+}
+
+func TestCapacityLessOrEqual_ShouldCompareEveryDimension(t *testing.T) {
+	cap1 := NewCapacityBuilder().AddDimension(0, 1).AddDimension(1, 2).Build()
+	cap2 := NewCapacityBuilder().AddDimension(0, 2).AddDimension(1, 2).Build()
+	cap3 := NewCapacityBuilder().AddDimension(0, 3).Build()
+	assert.True(t, cap1.IsLessOrEqual(cap2))
+	assert.False(t, cap2.IsLessOrEqual(cap1))
+	assert.False(t, cap1.IsLessOrEqual(cap3))
+	assert.True(t, cap2.IsGreaterOrEqual(cap1))
+	assert.False(t, cap3.IsGreaterOrEqual(cap1))
+}
@@ -1,6 +1,9 @@
 package cost
 
-import "gsprit/problem"
+import (
+	"gsprit/problem"
+	"math"
+)
 
 type ForwardTransportTime interface {
 	TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64
@@ -57,3 +60,26 @@ var Time = struct {
 type Parameter interface {
 	PenaltyForMissedTimeWindow() float64
 }
+
+// vehicleSpeed returns the max velocity of the vehicle type, or defaultSpeed if there is no vehicle type or its
+// max velocity is unbounded.
+func vehicleSpeed(vehicle problem.Vehicle, defaultSpeed float64) float64 {
+	if vehicle == nil || vehicle.Type() == nil {
+		return defaultSpeed
+	}
+	maxVelocity := vehicle.Type().MaxVelocity()
+	if maxVelocity <= 0.0 || maxVelocity == math.MaxFloat64 {
+		return defaultSpeed
+	}
+	return maxVelocity
+}
+
+// vehicleTransportCost returns the distance times the costs per distance unit plus the transport time times the
+// costs per transport time unit of the vehicle type, or just the distance if there is no vehicle type.
+func vehicleTransportCost(distance, transportTime float64, vehicle problem.Vehicle) float64 {
+	if vehicle == nil || vehicle.Type() == nil {
+		return distance
+	}
+	costParams := vehicle.Type().VehicleCostParams()
+	return distance*costParams.PerDistanceUnit() + transportTime*costParams.PerTransportTimeUnit()
+}
@@ -6,20 +6,39 @@ import (
 	"math"
 )
 
-// CrowFlyCosts represents Euclidean distance-based travel costs.
+var _ VehicleRoutingTransportCosts = (*CrowFlyCosts)(nil)
+
+// CrowFlyCosts represents Euclidean distance-based travel costs. Locations without a coordinate are looked up by
+// id. Times and costs depend on the vehicle as those of EuclideanCosts.
 type CrowFlyCosts struct {
 	EuclideanCosts
 	locations func(id string) *util.Coordinate
 }
 
-// NewCrowFlyCosts initializes a new CrowFlyCosts with given locations.
+// NewCrowFlyCosts initializes a new CrowFlyCosts with given locations. Speed and detour factor default to those
+// of NewEuclideanCosts.
 func NewCrowFlyCosts(locations func(id string) *util.Coordinate) *CrowFlyCosts {
-	res := &CrowFlyCosts{locations: locations}
+	res := &CrowFlyCosts{
+		EuclideanCosts: *NewEuclideanCosts(),
+		locations:      locations,
+	}
 	res.Spi = res
 	return res
 }
 
-// CalculateDistance computes the Euclidean distance between two locations.
+func (c *CrowFlyCosts) TransportCost(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return c.transportCost(c.CalculateDistance(from, to), vehicle)
+}
+
+func (c *CrowFlyCosts) TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return c.transportTime(c.CalculateDistance(from, to), vehicle)
+}
+
+func (c *CrowFlyCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
+	return c.CalculateDistance(from, to)
+}
+
+// CalculateDistance computes the Euclidean distance between two locations, multiplied by the detour factor.
 func (c *CrowFlyCosts) CalculateDistance(fromLocation, toLocation *problem.Location) float64 {
 	var from, to *util.Coordinate
 
@@ -31,7 +50,7 @@ func (c *CrowFlyCosts) CalculateDistance(fromLocation, toLocation *problem.Locat
 		to = c.locations(toLocation.Id())
 	}
 
-	return calculateDistance(from, to)
+	return calculateDistance(from, to) * c.DetourFactor
 }
 
 // calculateDistance computes the Euclidean distance between two coordinates.
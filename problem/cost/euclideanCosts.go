@@ -6,6 +6,12 @@ import (
 	"math"
 )
 
+var _ VehicleRoutingTransportCosts = (*EuclideanCosts)(nil)
+
+// EuclideanCosts computes straight-line distances between the coordinates of locations, multiplied by
+// DetourFactor. Transport times are the distance divided by the max velocity of the vehicle type, or by Speed if
+// there is no vehicle type or its max velocity is unbounded. Transport costs are the distance times the costs per
+// distance unit plus the transport time times the costs per transport time unit of the vehicle type.
 type EuclideanCosts struct {
 	AbstractForwardVehicleRoutingTransportCosts
 	Speed        float64
@@ -24,21 +30,25 @@ func NewEuclideanCosts() *EuclideanCosts {
 }
 
 func (e *EuclideanCosts) TransportCost(from, to *problem.Location, time float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
-	distance := e.calculateDistance(from, to)
-	if vehicle != nil && vehicle.Type() != nil {
-		return distance * vehicle.Type().VehicleCostParams().PerDistanceUnit()
-	}
-	return distance
+	return e.transportCost(e.calculateDistance(from, to), vehicle)
 }
 
 func (e *EuclideanCosts) TransportTime(from, to *problem.Location, time float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
-	return e.calculateDistance(from, to) / e.Speed
+	return e.transportTime(e.calculateDistance(from, to), vehicle)
 }
 
 func (e *EuclideanCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
 	return e.calculateDistance(from, to)
 }
 
+func (e *EuclideanCosts) transportTime(distance float64, vehicle problem.Vehicle) float64 {
+	return distance / vehicleSpeed(vehicle, e.Speed)
+}
+
+func (e *EuclideanCosts) transportCost(distance float64, vehicle problem.Vehicle) float64 {
+	return vehicleTransportCost(distance, e.transportTime(distance, vehicle), vehicle)
+}
+
 func (e *EuclideanCosts) calculateDistance(from, to *problem.Location) float64 {
 	return e.calculateDistanceCoords(from.Coordinate(), to.Coordinate())
 }
@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/vehicle"
+	"gsprit/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMixedFleet(start *problem.Location) (bike, van *vehicle.Vehicle) {
+	bikeType := vehicle.NewVehicleTypeBuilder("bike").SetMaxVelocity(2.).SetCostPerDistance(1.).SetCostPerTransportTime(3.).Build()
+	vanType := vehicle.NewVehicleTypeBuilder("van").SetCostPerDistance(2.).Build()
+	return vehicle.NewVehicleBuilder("bike").SetStartLocation(start).SetType(bikeType).Build(),
+		vehicle.NewVehicleBuilder("van").SetStartLocation(start).SetType(vanType).Build()
+}
+
+func TestEuclideanCosts_ShouldDeriveTimeAndCostsFromVehicleType(t *testing.T) {
+	costs := NewEuclideanCosts()
+	costs.Speed = 5.
+	from, to := problem.NewLocationWithCoordinate(0., 0.), problem.NewLocationWithCoordinate(30., 40.)
+	bike, van := newMixedFleet(from)
+
+	assert.Equal(t, 25., costs.TransportTime(from, to, 0., nil, bike))
+	assert.Equal(t, 50.+3.*25., costs.TransportCost(from, to, 0., nil, bike))
+	assert.Equal(t, 10., costs.TransportTime(from, to, 0., nil, van))
+	assert.Equal(t, 100., costs.TransportCost(from, to, 0., nil, van))
+	assert.Equal(t, 10., costs.TransportTime(from, to, 0., nil, nil))
+	assert.Equal(t, 50., costs.TransportCost(from, to, 0., nil, nil))
+	assert.Equal(t, 25., costs.BackwardTransportTime(to, from, 0., nil, bike))
+}
+
+func TestCrowFlyCosts_ShouldDeriveTimeAndCostsFromVehicleType(t *testing.T) {
+	coordinates := map[string]*util.Coordinate{"to": util.NewCoordinate(30., 40.)}
+	costs := NewCrowFlyCosts(func(id string) *util.Coordinate {
+		return coordinates[id]
+	})
+	costs.DetourFactor = 2.
+	from := problem.NewLocationWithCoordinate(0., 0.)
+	to := problem.NewLocationBuilder().SetId("to").SetCoordinate(util.NewCoordinate(30., 40.)).Build()
+	toById := problem.NewLocationWithID("to")
+	coordinates[from.Id()] = from.Coordinate()
+	bike, van := newMixedFleet(from)
+
+	assert.Equal(t, 100., costs.Distance(from, to, 0., nil))
+	assert.Equal(t, 100., costs.Distance(problem.NewLocationWithID(from.Id()), toById, 0., nil))
+	assert.Equal(t, 50., costs.TransportTime(from, toById, 0., nil, bike))
+	assert.Equal(t, 100.+3.*50., costs.TransportCost(from, to, 0., nil, bike))
+	assert.Equal(t, 100., costs.TransportTime(from, to, 0., nil, van))
+	assert.Equal(t, 200., costs.BackwardTransportCost(to, from, 0., nil, van))
+}
+
+func TestCrowFlyCosts_ShouldDefaultToSpeedAndDetourFactorOfEuclideanCosts(t *testing.T) {
+	costs := NewCrowFlyCosts(nil)
+	from, to := problem.NewLocationWithCoordinate(0., 0.), problem.NewLocationWithCoordinate(30., 40.)
+
+	assert.Equal(t, NewEuclideanCosts().Speed, costs.Speed)
+	assert.Equal(t, NewEuclideanCosts().DetourFactor, costs.DetourFactor)
+	assert.Equal(t, 50., costs.Distance(from, to, 0., nil))
+	assert.Equal(t, 50., costs.TransportTime(from, to, 0., nil, nil))
+	assert.Equal(t, 50., costs.TransportCost(from, to, 0., nil, nil))
+}
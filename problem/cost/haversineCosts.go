@@ -0,0 +1,71 @@
+package cost
+
+import (
+	"gsprit/problem"
+	"gsprit/util"
+	"math"
+)
+
+const (
+	// EarthRadius is the mean radius of the earth in metres.
+	EarthRadius = 6371000.0
+	// DefaultSpeed is the speed in metres per second of vehicle types without a max velocity, i.e. 50 km/h.
+	DefaultSpeed = 50.0 / 3.6
+)
+
+var _ VehicleRoutingTransportCosts = (*HaversineCosts)(nil)
+
+// HaversineCosts computes great-circle distances in metres between WGS84 coordinates, taking X as longitude and
+// Y as latitude, both in degrees. The distance is multiplied by DetourFactor to approximate the road network.
+//
+// Transport times are the distance divided by the max velocity of the vehicle type in metres per second, or by
+// DefaultSpeed if the vehicle type has no max velocity. Transport costs are the distance times the costs per
+// distance unit plus the transport time times the costs per transport time unit of the vehicle type.
+type HaversineCosts struct {
+	AbstractForwardVehicleRoutingTransportCosts
+	DetourFactor float64
+	DefaultSpeed float64
+}
+
+func NewHaversineCosts() *HaversineCosts {
+	res := &HaversineCosts{
+		DetourFactor: 1.0,
+		DefaultSpeed: DefaultSpeed,
+	}
+	res.Spi = res
+	return res
+}
+
+func (h *HaversineCosts) TransportCost(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	distance := h.calculateDistance(from, to)
+	return vehicleTransportCost(distance, distance/vehicleSpeed(vehicle, h.DefaultSpeed), vehicle)
+}
+
+func (h *HaversineCosts) TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return h.calculateDistance(from, to) / vehicleSpeed(vehicle, h.DefaultSpeed)
+}
+
+func (h *HaversineCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
+	return h.calculateDistance(from, to)
+}
+
+func (h *HaversineCosts) calculateDistance(from, to *problem.Location) float64 {
+	if from.Coordinate() == nil || to.Coordinate() == nil {
+		panic("Cannot calculate haversine distance. Coordinates are missing.")
+	}
+	return HaversineDistance(from.Coordinate(), to.Coordinate()) * h.DetourFactor
+}
+
+// HaversineDistance returns the great-circle distance in metres between two coordinates with X as longitude and
+// Y as latitude in degrees.
+func HaversineDistance(from, to *util.Coordinate) float64 {
+	lat1, lat2 := from.Y*math.Pi/180, to.Y*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (to.X - from.X) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1.0, math.Sqrt(a)))
+}
+
+func (h *HaversineCosts) String() string {
+	return "[name=haversineCosts]"
+}
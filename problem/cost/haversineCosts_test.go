@@ -0,0 +1,51 @@
+package cost
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/vehicle"
+	"gsprit/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaversineDistance_ShouldReturnGreatCircleDistanceInMetres(t *testing.T) {
+	berlin := util.NewCoordinate(13.4050, 52.5200)
+	paris := util.NewCoordinate(2.3522, 48.8566)
+
+	assert.InDelta(t, 877500., HaversineDistance(berlin, paris), 1000.)
+	assert.InDelta(t, 111195., HaversineDistance(util.NewCoordinate(0., 0.), util.NewCoordinate(1., 0.)), 1.)
+	assert.InDelta(t, 111195., HaversineDistance(util.NewCoordinate(7., 10.), util.NewCoordinate(7., 11.)), 1.)
+	assert.Equal(t, 0., HaversineDistance(berlin, berlin))
+}
+
+func TestHaversineCosts_ShouldApplyDetourFactor(t *testing.T) {
+	costs := NewHaversineCosts()
+	costs.DetourFactor = 1.3
+	from, to := problem.NewLocationWithCoordinate(0., 0.), problem.NewLocationWithCoordinate(1., 0.)
+
+	assert.InDelta(t, 1.3*111195., costs.Distance(from, to, 0., nil), 1.)
+}
+
+func TestHaversineCosts_ShouldDeriveTimeFromMaxVelocity(t *testing.T) {
+	costs := NewHaversineCosts()
+	from, to := problem.NewLocationWithCoordinate(0., 0.), problem.NewLocationWithCoordinate(1., 0.)
+	distance := costs.Distance(from, to, 0., nil)
+	bikeType := vehicle.NewVehicleTypeBuilder("bike").SetMaxVelocity(5.).SetCostPerDistance(1.).SetCostPerTransportTime(2.).Build()
+	bike := vehicle.NewVehicleBuilder("bike").SetStartLocation(from).SetType(bikeType).Build()
+	van := vehicle.NewVehicleBuilder("van").SetStartLocation(from).Build()
+
+	assert.InDelta(t, distance/5., costs.TransportTime(from, to, 0., nil, bike), 1e-9)
+	assert.InDelta(t, distance+2.*distance/5., costs.TransportCost(from, to, 0., nil, bike), 1e-9)
+	assert.InDelta(t, distance/DefaultSpeed, costs.TransportTime(from, to, 0., nil, van), 1e-9)
+	assert.InDelta(t, distance/DefaultSpeed, costs.BackwardTransportTime(to, from, 0., nil, nil), 1e-9)
+}
+
+func TestHaversineCosts_ShouldPanicWithoutCoordinates(t *testing.T) {
+	costs := NewHaversineCosts()
+
+	assert.Panics(t, func() {
+		costs.Distance(problem.NewLocationWithIndex(0), problem.NewLocationWithCoordinate(1., 0.), 0., nil)
+	})
+}
@@ -0,0 +1,159 @@
+package cost
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"slices"
+)
+
+var _ VehicleRoutingTransportCosts = (*MatrixTransportCosts)(nil)
+
+// MatrixTransportCosts looks up distances and transport times in dense matrices indexed by Location.Index, thus
+// every location has to have an index smaller than the number of locations. The matrices are flat slices; a
+// symmetric matrix only stores its upper triangle.
+//
+// Transport costs are the distance times the costs per distance unit plus the transport time times the costs per
+// transport time unit of the vehicle type, or just the distance if there is no vehicle. Lookups neither allocate
+// nor depend on the departure time.
+type MatrixTransportCosts struct {
+	AbstractForwardVehicleRoutingTransportCosts
+	numberOfLocations int
+	symmetric         bool
+	distances         []float64
+	times             []float64
+}
+
+func (m *MatrixTransportCosts) TransportCost(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	i := m.index(from, to)
+	return vehicleTransportCost(m.distances[i], m.times[i], vehicle)
+}
+
+func (m *MatrixTransportCosts) TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return m.times[m.index(from, to)]
+}
+
+func (m *MatrixTransportCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
+	return m.distances[m.index(from, to)]
+}
+
+func (m *MatrixTransportCosts) NumberOfLocations() int {
+	return m.numberOfLocations
+}
+
+func (m *MatrixTransportCosts) IsSymmetric() bool {
+	return m.symmetric
+}
+
+// index returns the position of the entry from -> to in the flat matrices.
+func (m *MatrixTransportCosts) index(from, to *problem.Location) int {
+	i, j := from.Index(), to.Index()
+	if uint(i) >= uint(m.numberOfLocations) || uint(j) >= uint(m.numberOfLocations) {
+		panic(fmt.Sprintf("location index out of range [0,%d): %v -> %v", m.numberOfLocations, from, to))
+	}
+	return matrixIndex(m.numberOfLocations, m.symmetric, i, j)
+}
+
+// matrixIndex returns the position of the entry (i,j) in a flat matrix of n locations. The upper triangle of a
+// symmetric matrix is stored row by row.
+func matrixIndex(n int, symmetric bool, i, j int) int {
+	if !symmetric {
+		return i*n + j
+	}
+	if i > j {
+		i, j = j, i
+	}
+	return i*(2*n-i+1)/2 + j - i
+}
+
+func (m *MatrixTransportCosts) String() string {
+	return fmt.Sprintf("[name=matrixTransportCosts][numberOfLocations=%d][symmetric=%v]", m.numberOfLocations, m.symmetric)
+}
+
+// MatrixTransportCostsBuilder fills the matrices of MatrixTransportCosts entry by entry, either by location index
+// or by location id. Ids have to be mapped to indices with AddLocation first. Entries not set are zero; setting
+// an entry of a symmetric matrix sets both directions.
+type MatrixTransportCostsBuilder struct {
+	numberOfLocations int
+	symmetric         bool
+	indices           map[string]int
+	distances         []float64
+	times             []float64
+}
+
+func NewMatrixTransportCostsBuilder(numberOfLocations int, symmetric bool) *MatrixTransportCostsBuilder {
+	if numberOfLocations < 0 {
+		panic("number of locations must not be negative")
+	}
+	size := numberOfLocations * numberOfLocations
+	if symmetric {
+		size = numberOfLocations * (numberOfLocations + 1) / 2
+	}
+	return &MatrixTransportCostsBuilder{
+		numberOfLocations: numberOfLocations,
+		symmetric:         symmetric,
+		indices:           make(map[string]int),
+		distances:         make([]float64, size),
+		times:             make([]float64, size),
+	}
+}
+
+// AddLocation maps the id of location to its index.
+func (b *MatrixTransportCostsBuilder) AddLocation(location *problem.Location) *MatrixTransportCostsBuilder {
+	return b.AddLocationIndex(location.Id(), location.Index())
+}
+
+// AddLocationIndex maps id to index.
+func (b *MatrixTransportCostsBuilder) AddLocationIndex(id string, index int) *MatrixTransportCostsBuilder {
+	b.checkIndex(index)
+	b.indices[id] = index
+	return b
+}
+
+func (b *MatrixTransportCostsBuilder) AddDistance(fromIndex, toIndex int, distance float64) *MatrixTransportCostsBuilder {
+	b.distances[b.entry(fromIndex, toIndex)] = distance
+	return b
+}
+
+func (b *MatrixTransportCostsBuilder) AddTransportTime(fromIndex, toIndex int, time float64) *MatrixTransportCostsBuilder {
+	b.times[b.entry(fromIndex, toIndex)] = time
+	return b
+}
+
+func (b *MatrixTransportCostsBuilder) AddDistanceById(fromId, toId string, distance float64) *MatrixTransportCostsBuilder {
+	return b.AddDistance(b.indexOf(fromId), b.indexOf(toId), distance)
+}
+
+func (b *MatrixTransportCostsBuilder) AddTransportTimeById(fromId, toId string, time float64) *MatrixTransportCostsBuilder {
+	return b.AddTransportTime(b.indexOf(fromId), b.indexOf(toId), time)
+}
+
+func (b *MatrixTransportCostsBuilder) Build() *MatrixTransportCosts {
+	res := &MatrixTransportCosts{
+		numberOfLocations: b.numberOfLocations,
+		symmetric:         b.symmetric,
+		distances:         slices.Clone(b.distances),
+		times:             slices.Clone(b.times),
+	}
+	res.Spi = res
+	return res
+}
+
+func (b *MatrixTransportCostsBuilder) entry(fromIndex, toIndex int) int {
+	b.checkIndex(fromIndex)
+	b.checkIndex(toIndex)
+	return matrixIndex(b.numberOfLocations, b.symmetric, fromIndex, toIndex)
+}
+
+func (b *MatrixTransportCostsBuilder) indexOf(id string) int {
+	index, ok := b.indices[id]
+	if !ok {
+		panic(fmt.Sprintf("location %s has not been added", id))
+	}
+	return index
+}
+
+func (b *MatrixTransportCostsBuilder) checkIndex(index int) {
+	if index < 0 || index >= b.numberOfLocations {
+		panic(fmt.Sprintf("location index %d out of range [0,%d)", index, b.numberOfLocations))
+	}
+}
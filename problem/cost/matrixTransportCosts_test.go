@@ -0,0 +1,99 @@
+package cost
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/vehicle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsymmetricMatrix_ShouldReturnEntriesPerDirection(t *testing.T) {
+	costs := NewMatrixTransportCostsBuilder(3, false).
+		AddDistance(0, 1, 10.).AddDistance(1, 0, 20.).
+		AddTransportTime(0, 2, 5.).AddTransportTime(2, 0, 7.).
+		Build()
+	l0, l1, l2 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1), problem.NewLocationWithIndex(2)
+
+	assert.Equal(t, 10., costs.Distance(l0, l1, 0., nil))
+	assert.Equal(t, 20., costs.Distance(l1, l0, 0., nil))
+	assert.Equal(t, 5., costs.TransportTime(l0, l2, 0., nil, nil))
+	assert.Equal(t, 7., costs.TransportTime(l2, l0, 0., nil, nil))
+	assert.Equal(t, 0., costs.Distance(l1, l2, 0., nil))
+	assert.False(t, costs.IsSymmetric())
+}
+
+func TestSymmetricMatrix_ShouldSetBothDirections(t *testing.T) {
+	costs := NewMatrixTransportCostsBuilder(4, true).
+		AddDistance(3, 1, 10.).AddDistance(0, 2, 20.).
+		AddTransportTime(1, 3, 5.).
+		Build()
+	l0, l1, l2, l3 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1), problem.NewLocationWithIndex(2),
+		problem.NewLocationWithIndex(3)
+
+	assert.Equal(t, 10., costs.Distance(l1, l3, 0., nil))
+	assert.Equal(t, 10., costs.Distance(l3, l1, 0., nil))
+	assert.Equal(t, 20., costs.Distance(l2, l0, 0., nil))
+	assert.Equal(t, 5., costs.TransportTime(l3, l1, 0., nil, nil))
+	assert.Equal(t, 0., costs.Distance(l3, l3, 0., nil))
+	assert.True(t, costs.IsSymmetric())
+}
+
+func TestMatrixEntriesById_ShouldBeFoundByLocationIndex(t *testing.T) {
+	depot := problem.NewLocationBuilder().SetId("depot").SetIndex(1).Build()
+	customer := problem.NewLocationBuilder().SetId("customer").SetIndex(0).Build()
+	costs := NewMatrixTransportCostsBuilder(2, false).
+		AddLocation(depot).AddLocation(customer).
+		AddDistanceById("depot", "customer", 3.).
+		AddTransportTimeById("customer", "depot", 4.).
+		Build()
+
+	assert.Equal(t, 3., costs.Distance(depot, customer, 0., nil))
+	assert.Equal(t, 4., costs.TransportTime(customer, depot, 0., nil, nil))
+	assert.Equal(t, 0., costs.Distance(customer, depot, 0., nil))
+}
+
+func TestMatrixTransportCost_ShouldCombineDistanceAndTimeCostsOfVehicle(t *testing.T) {
+	costs := NewMatrixTransportCostsBuilder(2, true).
+		AddDistance(0, 1, 100.).AddTransportTime(0, 1, 10.).
+		Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	vehicleType := vehicle.NewVehicleTypeBuilder("type").SetCostPerDistance(2.).SetCostPerTransportTime(3.).Build()
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(l0).SetType(vehicleType).Build()
+
+	assert.Equal(t, 230., costs.TransportCost(l0, l1, 0., nil, v))
+	assert.Equal(t, 100., costs.TransportCost(l0, l1, 0., nil, nil))
+	assert.Equal(t, 10., costs.BackwardTransportTime(l1, l0, 50., nil, v))
+}
+
+func TestMatrixTransportCosts_ShouldPanicOnLocationOutOfRange(t *testing.T) {
+	costs := NewMatrixTransportCostsBuilder(2, false).Build()
+	l0 := problem.NewLocationWithIndex(0)
+
+	assert.Panics(t, func() {
+		costs.Distance(l0, problem.NewLocationWithIndex(2), 0., nil)
+	})
+	assert.Panics(t, func() {
+		costs.TransportTime(problem.NewLocationWithID("noIndex"), l0, 0., nil, nil)
+	})
+	assert.Panics(t, func() {
+		NewMatrixTransportCostsBuilder(2, false).AddDistance(0, 2, 1.)
+	})
+	assert.Panics(t, func() {
+		NewMatrixTransportCostsBuilder(2, false).AddDistanceById("a", "b", 1.)
+	})
+}
+
+func TestMatrixLookups_ShouldNotAllocate(t *testing.T) {
+	costs := NewMatrixTransportCostsBuilder(2, true).AddDistance(0, 1, 1.).AddTransportTime(0, 1, 1.).Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(l0).Build()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		costs.Distance(l0, l1, 0., v)
+		costs.TransportTime(l1, l0, 0., nil, v)
+		costs.TransportCost(l0, l1, 0., nil, v)
+	})
+	assert.Equal(t, 0., allocs)
+}
@@ -0,0 +1,125 @@
+package cost
+
+import (
+	"fmt"
+	"gsprit/problem"
+	"sort"
+	"strings"
+)
+
+var _ VehicleRoutingTransportCosts = (*ProfileTransportCosts)(nil)
+
+// ProfileDependent is implemented by transport costs that depend on the profile of the vehicle type, e.g. because
+// bikes, vans and trucks use different road networks. The problem builder checks that every profile of its
+// vehicle types is supported.
+type ProfileDependent interface {
+	HasProfile(profile string) bool
+}
+
+// ProfileTransportCosts holds transport costs per vehicle profile and dispatches every call on
+// vehicle.Type().Profile(). Calls without a vehicle, a vehicle type or a profile use the costs of the default
+// profile. Backward times and costs are dispatched as well, thus the costs of a profile may depend on time.
+type ProfileTransportCosts struct {
+	defaultProfile string
+	defaultCosts   VehicleRoutingTransportCosts
+	profiles       map[string]VehicleRoutingTransportCosts
+}
+
+func (p *ProfileTransportCosts) TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return p.costsOf(vehicle).TransportTime(from, to, departureTime, driver, vehicle)
+}
+
+func (p *ProfileTransportCosts) BackwardTransportTime(from, to *problem.Location, arrivalTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return p.costsOf(vehicle).BackwardTransportTime(from, to, arrivalTime, driver, vehicle)
+}
+
+func (p *ProfileTransportCosts) TransportCost(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return p.costsOf(vehicle).TransportCost(from, to, departureTime, driver, vehicle)
+}
+
+func (p *ProfileTransportCosts) BackwardTransportCost(from, to *problem.Location, arrivalTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return p.costsOf(vehicle).BackwardTransportCost(from, to, arrivalTime, driver, vehicle)
+}
+
+func (p *ProfileTransportCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
+	return p.costsOf(vehicle).Distance(from, to, departureTime, vehicle)
+}
+
+func (p *ProfileTransportCosts) HasProfile(profile string) bool {
+	_, ok := p.profiles[profile]
+	return ok || profile == ""
+}
+
+func (p *ProfileTransportCosts) DefaultProfile() string {
+	return p.defaultProfile
+}
+
+// Costs returns the transport costs of profile.
+func (p *ProfileTransportCosts) Costs(profile string) (VehicleRoutingTransportCosts, bool) {
+	costs, ok := p.profiles[profile]
+	return costs, ok
+}
+
+func (p *ProfileTransportCosts) costsOf(vehicle problem.Vehicle) VehicleRoutingTransportCosts {
+	if vehicle == nil || vehicle.Type() == nil || vehicle.Type().Profile() == "" {
+		return p.defaultCosts
+	}
+	costs, ok := p.profiles[vehicle.Type().Profile()]
+	if !ok {
+		panic(fmt.Sprintf("No transport costs for profile %s of vehicle %s.", vehicle.Type().Profile(), vehicle.Id()))
+	}
+	return costs
+}
+
+func (p *ProfileTransportCosts) String() string {
+	profiles := make([]string, 0, len(p.profiles))
+	for profile := range p.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return fmt.Sprintf("[name=profileTransportCosts][defaultProfile=%s][profiles=%s]", p.defaultProfile, strings.Join(profiles, ","))
+}
+
+// ProfileTransportCostsBuilder collects the transport costs of every profile. The costs of the default profile
+// have to be added as well.
+type ProfileTransportCostsBuilder struct {
+	defaultProfile string
+	profiles       map[string]VehicleRoutingTransportCosts
+}
+
+func NewProfileTransportCostsBuilder(defaultProfile string) *ProfileTransportCostsBuilder {
+	if defaultProfile == "" {
+		panic("Default profile must not be empty.")
+	}
+	return &ProfileTransportCostsBuilder{
+		defaultProfile: defaultProfile,
+		profiles:       make(map[string]VehicleRoutingTransportCosts),
+	}
+}
+
+func (b *ProfileTransportCostsBuilder) AddProfile(profile string, costs VehicleRoutingTransportCosts) *ProfileTransportCostsBuilder {
+	if profile == "" {
+		panic("Profile must not be empty.")
+	}
+	if costs == nil {
+		panic(fmt.Sprintf("Transport costs of profile %s must not be nil.", profile))
+	}
+	b.profiles[profile] = costs
+	return b
+}
+
+func (b *ProfileTransportCostsBuilder) Build() *ProfileTransportCosts {
+	defaultCosts, ok := b.profiles[b.defaultProfile]
+	if !ok {
+		panic(fmt.Sprintf("No transport costs for default profile %s.", b.defaultProfile))
+	}
+	profiles := make(map[string]VehicleRoutingTransportCosts, len(b.profiles))
+	for profile, costs := range b.profiles {
+		profiles[profile] = costs
+	}
+	return &ProfileTransportCosts{
+		defaultProfile: b.defaultProfile,
+		defaultCosts:   defaultCosts,
+		profiles:       profiles,
+	}
+}
@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/vehicle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newProfileTestMatrix(distance, time float64) *MatrixTransportCosts {
+	return NewMatrixTransportCostsBuilder(2, true).AddDistance(0, 1, distance).AddTransportTime(0, 1, time).Build()
+}
+
+func TestProfileTransportCosts_ShouldDispatchOnProfileOfVehicleType(t *testing.T) {
+	costs := NewProfileTransportCostsBuilder("car").
+		AddProfile("car", newProfileTestMatrix(10., 1.)).
+		AddProfile("bike", newProfileTestMatrix(8., 4.)).
+		Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	bikeType := vehicle.NewVehicleTypeBuilder("bike").SetProfile("bike").Build()
+	bike := vehicle.NewVehicleBuilder("bike").SetStartLocation(l0).SetType(bikeType).Build()
+	car := vehicle.NewVehicleBuilder("car").SetStartLocation(l0).Build()
+
+	assert.Equal(t, 8., costs.Distance(l0, l1, 0., bike))
+	assert.Equal(t, 4., costs.TransportTime(l0, l1, 0., nil, bike))
+	assert.Equal(t, 4., costs.BackwardTransportTime(l1, l0, 10., nil, bike))
+	assert.Equal(t, 10., costs.Distance(l0, l1, 0., car))
+	assert.Equal(t, 1., costs.TransportTime(l0, l1, 0., nil, car))
+}
+
+func TestProfileTransportCosts_ShouldFallBackToDefaultProfile(t *testing.T) {
+	costs := NewProfileTransportCostsBuilder("car").
+		AddProfile("car", newProfileTestMatrix(10., 1.)).
+		AddProfile("bike", newProfileTestMatrix(8., 4.)).
+		Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	noProfileType := vehicle.NewVehicleTypeBuilder("type").SetProfile("").Build()
+	v := vehicle.NewVehicleBuilder("v").SetStartLocation(l0).SetType(noProfileType).Build()
+
+	assert.Equal(t, 10., costs.Distance(l0, l1, 0., nil))
+	assert.Equal(t, 10., costs.TransportCost(l0, l1, 0., nil, nil))
+	assert.Equal(t, 10., costs.Distance(l0, l1, 0., v))
+	assert.True(t, costs.HasProfile("bike"))
+	assert.False(t, costs.HasProfile("hgv"))
+}
+
+func TestProfileTransportCosts_ShouldPanicOnUnknownProfile(t *testing.T) {
+	costs := NewProfileTransportCostsBuilder("car").AddProfile("car", newProfileTestMatrix(10., 1.)).Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	hgvType := vehicle.NewVehicleTypeBuilder("hgv").SetProfile("hgv").Build()
+	hgv := vehicle.NewVehicleBuilder("hgv").SetStartLocation(l0).SetType(hgvType).Build()
+
+	assert.Panics(t, func() {
+		costs.Distance(l0, l1, 0., hgv)
+	})
+}
+
+func TestProfileTransportCostsWithoutDefaultProfile_ShouldPanic(t *testing.T) {
+	assert.Panics(t, func() {
+		NewProfileTransportCostsBuilder("car").AddProfile("bike", newProfileTestMatrix(8., 4.)).Build()
+	})
+}
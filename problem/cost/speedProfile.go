@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SpeedProfile divides time into buckets with a piecewise-constant speed factor, e.g. 0.5 during rush hour. A
+// bucket lasts from its start until the start of the next bucket; the first bucket also covers the time before
+// its start and the last bucket lasts forever.
+//
+// Travel times follow Ichoua, Gendreau and Potvin: the speed changes when the vehicle crosses a bucket boundary
+// rather than depending on the departure time only. Thus a vehicle departing later never arrives earlier, i.e.
+// the first-in-first-out property holds.
+type SpeedProfile struct {
+	starts  []float64
+	factors []float64
+}
+
+// TravelTime returns the time needed to travel an arc with the given free-flow time, i.e. the travel time at
+// speed factor 1, departing at departureTime.
+func (p *SpeedProfile) TravelTime(departureTime, freeFlowTime float64) float64 {
+	t, remaining := departureTime, freeFlowTime
+	for k := p.bucket(departureTime); ; k++ {
+		end := math.Inf(1)
+		if k+1 < len(p.starts) {
+			end = p.starts[k+1]
+		}
+		if reachable := (end - t) * p.factors[k]; reachable < remaining {
+			remaining -= reachable
+			t = end
+			continue
+		}
+		return t + remaining/p.factors[k] - departureTime
+	}
+}
+
+// BackwardTravelTime returns the time needed to travel an arc with the given free-flow time, arriving at
+// arrivalTime. It is the inverse of TravelTime, i.e. departing at arrivalTime minus the backward travel time
+// the vehicle arrives at arrivalTime.
+func (p *SpeedProfile) BackwardTravelTime(arrivalTime, freeFlowTime float64) float64 {
+	t, remaining := arrivalTime, freeFlowTime
+	for k := p.bucketBefore(arrivalTime); ; k-- {
+		start := math.Inf(-1)
+		if k > 0 {
+			start = p.starts[k]
+		}
+		if reachable := (t - start) * p.factors[k]; reachable < remaining {
+			remaining -= reachable
+			t = start
+			continue
+		}
+		return arrivalTime - (t - remaining/p.factors[k])
+	}
+}
+
+// bucket returns the bucket containing time.
+func (p *SpeedProfile) bucket(time float64) int {
+	k := sort.Search(len(p.starts), func(i int) bool {
+		return p.starts[i] > time
+	})
+	return max(k-1, 0)
+}
+
+// bucketBefore returns the bucket containing the instant just before time.
+func (p *SpeedProfile) bucketBefore(time float64) int {
+	k := sort.SearchFloat64s(p.starts, time)
+	return max(k-1, 0)
+}
+
+func (p *SpeedProfile) String() string {
+	return fmt.Sprintf("[name=speedProfile][starts=%v][factors=%v]", p.starts, p.factors)
+}
+
+// SpeedProfileBuilder adds the buckets of a SpeedProfile in the order of their start times.
+type SpeedProfileBuilder struct {
+	starts  []float64
+	factors []float64
+}
+
+func NewSpeedProfileBuilder() *SpeedProfileBuilder {
+	return &SpeedProfileBuilder{}
+}
+
+// AddBucket adds a bucket starting at start with the given speed factor.
+func (b *SpeedProfileBuilder) AddBucket(start, factor float64) *SpeedProfileBuilder {
+	if factor <= 0.0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		panic(fmt.Sprintf("Speed factor must be positive and finite, but is %f.", factor))
+	}
+	if n := len(b.starts); n > 0 && start <= b.starts[n-1] {
+		panic(fmt.Sprintf("Buckets must be added in ascending order of their start, but %f follows %f.", start, b.starts[n-1]))
+	}
+	b.starts = append(b.starts, start)
+	b.factors = append(b.factors, factor)
+	return b
+}
+
+func (b *SpeedProfileBuilder) Build() *SpeedProfile {
+	if len(b.starts) == 0 {
+		panic("A speed profile needs at least one bucket.")
+	}
+	return &SpeedProfile{
+		starts:  append([]float64{}, b.starts...),
+		factors: append([]float64{}, b.factors...),
+	}
+}
@@ -0,0 +1,115 @@
+package cost
+
+import (
+	"fmt"
+	"gsprit/problem"
+)
+
+var (
+	_ VehicleRoutingTransportCosts = (*TimeDependentTransportCosts)(nil)
+	_ ProfileDependent             = (*TimeDependentTransportCosts)(nil)
+)
+
+// TimeDependentTransportCosts makes the transport times of base costs depend on the time of day. The transport
+// time of base is the free-flow time of an arc, which is stretched by the SpeedProfile of the vehicle's profile,
+// or by the default speed profile if its profile has none. Distances are those of base.
+//
+// Backward transport times and costs are computed from the arrival time, thus they are consistent with the
+// forward ones: departing the backward transport time before an arrival time, a vehicle arrives exactly then.
+//
+// Transport costs are the distance times the costs per distance unit plus the transport time times the costs per
+// transport time unit of the vehicle type, or just the distance if there is no vehicle.
+type TimeDependentTransportCosts struct {
+	base          VehicleRoutingTransportCosts
+	defaultSpeeds *SpeedProfile
+	speeds        map[string]*SpeedProfile
+}
+
+func (c *TimeDependentTransportCosts) TransportTime(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	freeFlowTime := c.base.TransportTime(from, to, departureTime, driver, vehicle)
+	return c.speedProfile(vehicle).TravelTime(departureTime, freeFlowTime)
+}
+
+func (c *TimeDependentTransportCosts) BackwardTransportTime(from, to *problem.Location, arrivalTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	freeFlowTime := c.base.BackwardTransportTime(from, to, arrivalTime, driver, vehicle)
+	return c.speedProfile(vehicle).BackwardTravelTime(arrivalTime, freeFlowTime)
+}
+
+func (c *TimeDependentTransportCosts) TransportCost(from, to *problem.Location, departureTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	return vehicleTransportCost(c.base.Distance(from, to, departureTime, vehicle),
+		c.TransportTime(from, to, departureTime, driver, vehicle), vehicle)
+}
+
+func (c *TimeDependentTransportCosts) BackwardTransportCost(from, to *problem.Location, arrivalTime float64, driver problem.Driver, vehicle problem.Vehicle) float64 {
+	transportTime := c.BackwardTransportTime(from, to, arrivalTime, driver, vehicle)
+	return vehicleTransportCost(c.base.Distance(from, to, arrivalTime-transportTime, vehicle), transportTime, vehicle)
+}
+
+func (c *TimeDependentTransportCosts) Distance(from, to *problem.Location, departureTime float64, vehicle problem.Vehicle) float64 {
+	return c.base.Distance(from, to, departureTime, vehicle)
+}
+
+// HasProfile reports whether base supports profile. Profiles without a speed profile use the default one.
+func (c *TimeDependentTransportCosts) HasProfile(profile string) bool {
+	if profileCosts, ok := c.base.(ProfileDependent); ok {
+		return profileCosts.HasProfile(profile)
+	}
+	return true
+}
+
+func (c *TimeDependentTransportCosts) speedProfile(vehicle problem.Vehicle) *SpeedProfile {
+	if vehicle == nil || vehicle.Type() == nil {
+		return c.defaultSpeeds
+	}
+	if speeds, ok := c.speeds[vehicle.Type().Profile()]; ok {
+		return speeds
+	}
+	return c.defaultSpeeds
+}
+
+func (c *TimeDependentTransportCosts) String() string {
+	return fmt.Sprintf("[name=timeDependentTransportCosts][base=%v][#speedProfiles=%d]", c.base, len(c.speeds))
+}
+
+// TimeDependentTransportCostsBuilder assigns speed profiles to vehicle profiles.
+type TimeDependentTransportCostsBuilder struct {
+	base          VehicleRoutingTransportCosts
+	defaultSpeeds *SpeedProfile
+	speeds        map[string]*SpeedProfile
+}
+
+// NewTimeDependentTransportCostsBuilder takes the free-flow costs and the speed profile of every vehicle profile
+// without a speed profile of its own.
+func NewTimeDependentTransportCostsBuilder(base VehicleRoutingTransportCosts, defaultSpeeds *SpeedProfile) *TimeDependentTransportCostsBuilder {
+	if base == nil {
+		panic("Base transport costs must not be nil.")
+	}
+	if defaultSpeeds == nil {
+		panic("Default speed profile must not be nil.")
+	}
+	return &TimeDependentTransportCostsBuilder{
+		base:          base,
+		defaultSpeeds: defaultSpeeds,
+		speeds:        make(map[string]*SpeedProfile),
+	}
+}
+
+func (b *TimeDependentTransportCostsBuilder) AddSpeedProfile(profile string, speeds *SpeedProfile) *TimeDependentTransportCostsBuilder {
+	if speeds == nil {
+		panic(fmt.Sprintf("Speed profile of profile %s must not be nil.", profile))
+	}
+	b.speeds[profile] = speeds
+	return b
+}
+
+func (b *TimeDependentTransportCostsBuilder) Build() *TimeDependentTransportCosts {
+	speeds := make(map[string]*SpeedProfile, len(b.speeds))
+	for profile, s := range b.speeds {
+		speeds[profile] = s
+	}
+	return &TimeDependentTransportCosts{
+		base:          b.base,
+		defaultSpeeds: b.defaultSpeeds,
+		speeds:        speeds,
+	}
+}
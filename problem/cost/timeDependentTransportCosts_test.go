@@ -0,0 +1,98 @@
+package cost
+
+import (
+	"testing"
+
+	"gsprit/problem"
+	"gsprit/problem/vehicle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rushHour halves the speed between 10 and 20.
+func rushHour() *SpeedProfile {
+	return NewSpeedProfileBuilder().AddBucket(0., 1.).AddBucket(10., 0.5).AddBucket(20., 1.).Build()
+}
+
+func TestSpeedProfile_ShouldChangeSpeedAtBucketBoundaries(t *testing.T) {
+	speeds := rushHour()
+
+	assert.Equal(t, 10., speeds.TravelTime(-5., 10.))
+	assert.Equal(t, 15., speeds.TravelTime(5., 10.))
+	assert.Equal(t, 10., speeds.TravelTime(10., 5.))
+	assert.Equal(t, 12.5, speeds.TravelTime(15., 10.))
+	assert.Equal(t, 10., speeds.TravelTime(20., 10.))
+	assert.Equal(t, 0., speeds.TravelTime(12., 0.))
+}
+
+func TestSpeedProfile_BackwardTravelTimeShouldStartFromArrivalTime(t *testing.T) {
+	speeds := rushHour()
+
+	assert.Equal(t, 15., speeds.BackwardTravelTime(20., 10.))
+	assert.Equal(t, 10., speeds.BackwardTravelTime(30., 10.))
+	assert.Equal(t, 10., speeds.BackwardTravelTime(10., 10.))
+	assert.Equal(t, 15., speeds.BackwardTravelTime(25., 10.))
+}
+
+func TestSpeedProfile_ShouldBeFirstInFirstOut(t *testing.T) {
+	speeds := NewSpeedProfileBuilder().AddBucket(0., 1.).AddBucket(10., 0.2).AddBucket(12., 3.).AddBucket(30., 0.7).Build()
+
+	lastArrival := -1.
+	for departure := -5.; departure < 40.; departure += 0.1 {
+		arrival := departure + speeds.TravelTime(departure, 7.)
+		assert.GreaterOrEqual(t, arrival, lastArrival)
+		assert.InDelta(t, departure, arrival-speeds.BackwardTravelTime(arrival, 7.), 1e-9)
+		lastArrival = arrival
+	}
+}
+
+func TestSpeedProfileBuilder_ShouldPanicOnInvalidBuckets(t *testing.T) {
+	assert.Panics(t, func() {
+		NewSpeedProfileBuilder().Build()
+	})
+	assert.Panics(t, func() {
+		NewSpeedProfileBuilder().AddBucket(0., 0.)
+	})
+	assert.Panics(t, func() {
+		NewSpeedProfileBuilder().AddBucket(10., 1.).AddBucket(10., 0.5)
+	})
+}
+
+func TestTimeDependentTransportCosts_ShouldUseSpeedProfileOfVehicleProfile(t *testing.T) {
+	base := NewMatrixTransportCostsBuilder(2, true).AddDistance(0, 1, 100.).AddTransportTime(0, 1, 10.).Build()
+	costs := NewTimeDependentTransportCostsBuilder(base, rushHour()).
+		AddSpeedProfile("bike", NewSpeedProfileBuilder().AddBucket(0., 1.).Build()).
+		Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	vanType := vehicle.NewVehicleTypeBuilder("van").SetCostPerDistance(1.).SetCostPerTransportTime(2.).Build()
+	van := vehicle.NewVehicleBuilder("van").SetStartLocation(l0).SetType(vanType).Build()
+	bikeType := vehicle.NewVehicleTypeBuilder("bike").SetProfile("bike").Build()
+	bike := vehicle.NewVehicleBuilder("bike").SetStartLocation(l0).SetType(bikeType).Build()
+
+	assert.Equal(t, 15., costs.TransportTime(l0, l1, 5., nil, van))
+	assert.Equal(t, 10., costs.TransportTime(l0, l1, 5., nil, bike))
+	assert.Equal(t, 15., costs.TransportTime(l0, l1, 5., nil, nil))
+	assert.Equal(t, 100., costs.Distance(l0, l1, 5., van))
+	assert.Equal(t, 130., costs.TransportCost(l0, l1, 5., nil, van))
+}
+
+func TestTimeDependentTransportCosts_BackwardShouldBeConsistentWithForward(t *testing.T) {
+	base := NewMatrixTransportCostsBuilder(2, true).AddDistance(0, 1, 100.).AddTransportTime(0, 1, 10.).Build()
+	costs := NewTimeDependentTransportCostsBuilder(base, rushHour()).Build()
+	l0, l1 := problem.NewLocationWithIndex(0), problem.NewLocationWithIndex(1)
+	vanType := vehicle.NewVehicleTypeBuilder("van").SetCostPerDistance(1.).SetCostPerTransportTime(2.).Build()
+	van := vehicle.NewVehicleBuilder("van").SetStartLocation(l0).SetType(vanType).Build()
+
+	assert.Equal(t, 15., costs.BackwardTransportTime(l0, l1, 20., nil, van))
+	assert.NotEqual(t, costs.TransportTime(l0, l1, 20., nil, van), costs.BackwardTransportTime(l0, l1, 20., nil, van))
+	assert.Equal(t, costs.TransportCost(l0, l1, 5., nil, van), costs.BackwardTransportCost(l0, l1, 20., nil, van))
+}
+
+func TestTimeDependentTransportCosts_ShouldSupportProfilesOfBase(t *testing.T) {
+	matrix := NewMatrixTransportCostsBuilder(1, true).Build()
+	base := NewProfileTransportCostsBuilder("car").AddProfile("car", matrix).Build()
+
+	assert.True(t, NewTimeDependentTransportCostsBuilder(base, rushHour()).Build().HasProfile("car"))
+	assert.False(t, NewTimeDependentTransportCostsBuilder(base, rushHour()).Build().HasProfile("hgv"))
+	assert.True(t, NewTimeDependentTransportCostsBuilder(matrix, rushHour()).Build().HasProfile("hgv"))
+}
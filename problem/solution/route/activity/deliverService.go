@@ -86,6 +86,7 @@ func (ds *DeliverService) SetEndTime(endTime float64) {
 // Duplicate creates a copy of the activity
 func (ds *DeliverService) Duplicate() problem.TourActivity {
 	return &DeliverService{
+		BaseActivity:        ds.BaseActivity,
 		delivery:            ds.delivery,
 		capacity:            ds.capacity,
 		arrTime:             ds.arrTime,
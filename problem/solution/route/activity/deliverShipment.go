@@ -31,6 +31,7 @@ func NewDeliverShipment(shipment problem.Shipment) *DeliverShipment {
 func (ds *DeliverShipment) Duplicate() problem.TourActivity {
 	return &DeliverShipment{
 		shipment: ds.shipment,
+		capacity: ds.capacity,
 		arrTime:  ds.arrTime,
 		endTime:  ds.endTime,
 		index:    ds.index,
@@ -83,6 +83,7 @@ func (e *End) Duplicate() problem.TourActivity {
 		theoreticalLatestOperationStart:   e.theoreticalLatestOperationStart,
 		endTime:                           e.endTime,
 		arrTime:                           e.arrTime,
+		capacity:                          e.capacity,
 	}
 	res.SetIndex(-2)
 	return res
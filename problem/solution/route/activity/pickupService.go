@@ -29,6 +29,7 @@ func NewPickupService(pickup problem.Pickup) *PickupService {
 // Duplicate creates a deep copy of PickupService.
 func (ps *PickupService) Duplicate() problem.TourActivity {
 	return &PickupService{
+		BaseActivity:        ps.BaseActivity,
 		pickup:              ps.pickup,
 		arrTime:             ps.arrTime,
 		depTime:             ps.depTime,
@@ -87,12 +87,13 @@ func (ps *PickupShipment) Size() *problem.Capacity {
 
 func (ps *PickupShipment) Duplicate() problem.TourActivity {
 	return &PickupShipment{
-		shipment: ps.shipment,
-		arrTime:  ps.arrTime,
-		endTime:  ps.endTime,
-		index:    ps.index,
-		earliest: ps.earliest,
-		latest:   ps.latest,
+		BaseActivity: ps.BaseActivity,
+		shipment:     ps.shipment,
+		arrTime:      ps.arrTime,
+		endTime:      ps.endTime,
+		index:        ps.index,
+		earliest:     ps.earliest,
+		latest:       ps.latest,
 	}
 }
 
@@ -83,6 +83,7 @@ func (s *Start) Duplicate() problem.TourActivity {
 		theoreticalLatestOperationStart:   s.theoreticalLatestOperationStart,
 		endTime:                           s.endTime,
 		arrTime:                           s.arrTime,
+		capacity:                          s.capacity,
 	}
 	res.SetIndex(-1)
 	return res
@@ -103,6 +104,7 @@ func (s *Start) Copy() *Start {
 		theoreticalEarliestOperationStart: s.TheoreticalEarliestOperationStartTime(),
 		theoreticalLatestOperationStart:   s.TheoreticalLatestOperationStartTime(),
 		endTime:                           s.EndTime(),
+		capacity:                          s.capacity,
 	}
 	res.SetIndex(-1)
 	return res
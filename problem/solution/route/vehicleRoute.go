@@ -260,7 +260,11 @@ func (vr *VehicleRoute) setStartAndEnd(vehicle problem.Vehicle, vehicleDepTime f
 	}
 	vr.start.SetEndTime(max(vehicleDepTime, vehicle.EarliestDeparture()))
 	vr.start.SetLocation(vehicle.StartLocation())
+	vr.start.SetTheoreticalEarliestOperationStartTime(vehicle.EarliestDeparture())
+	vr.start.SetTheoreticalLatestOperationStartTime(vehicle.LatestArrival())
 	vr.end.SetLocation(vehicle.EndLocation())
+	vr.end.SetTheoreticalEarliestOperationStartTime(vehicle.EarliestDeparture())
+	vr.end.SetTheoreticalLatestOperationStartTime(vehicle.LatestArrival())
 }
 
 func (vr *VehicleRoute) DepartureTime() (float64, error) {
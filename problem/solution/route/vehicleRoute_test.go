@@ -144,3 +144,26 @@ func TestAddingDeliveryToRoute(t *testing.T) {
 	assert.Equal(t, "delivery", act.Name())
 	assert.IsType(t, &activity.DeliverService{}, act)
 }
+
+func TestCopyingRoute_ShouldDuplicateActivitiesWithIndexAndSize(t *testing.T) {
+	pickup := job.NewPickupBuilder("pick").AddSizeDimension(0, 1).SetLocation(problem.NewLocationWithID("pickLoc")).Build()
+	delivery := job.NewDeliveryBuilder("delivery").AddSizeDimension(0, 2).SetLocation(problem.NewLocationWithID("deliveryLoc")).Build()
+	shipment := job.NewShipmentBuilder("shipment").AddSizeDimension(0, 3).SetPickupLocation(problem.NewLocationWithID("shipmentPickupLoc")).
+		SetDeliveryLocation(problem.NewLocationWithID("shipmentDeliveryLoc")).Build()
+	r := NewVehicleRouteBuilder(testVehicle, testDriver).AddService(pickup).AddService(delivery).
+		AddPickupForShipment(shipment).AddDeliveryForShipment(shipment).Build()
+	for i, act := range r.Activities() {
+		act.(problem.AbstractActivity).SetIndex(i + 1)
+	}
+
+	copied := r.Copy()
+
+	assert.Equal(t, len(r.Activities()), len(copied.Activities()))
+	for i, act := range r.Activities() {
+		assert.Equal(t, act.Index(), copied.Activities()[i].Index(), act.Name())
+		assert.Equal(t, act.Size(), copied.Activities()[i].Size(), act.Name())
+	}
+	assert.Equal(t, r.Start().Size(), copied.Start().Size())
+	assert.Equal(t, r.Start().Size(), r.Start().Duplicate().Size())
+	assert.Equal(t, r.End().Size(), r.End().Duplicate().Size())
+}
@@ -51,6 +51,11 @@ func (v *VehicleRoutingProblemSolution) Routes() []*route.VehicleRoute {
 	return v.routes
 }
 
+// SetRoutes replaces the collection of vehicle routes
+func (v *VehicleRoutingProblemSolution) SetRoutes(routes []*route.VehicleRoute) {
+	v.routes = routes
+}
+
 // getCost returns the cost of the solution
 func (v *VehicleRoutingProblemSolution) Cost() float64 {
 	return v.cost
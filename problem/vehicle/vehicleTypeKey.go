@@ -3,6 +3,8 @@ package vehicle
 import (
 	"fmt"
 	"gsprit/problem"
+	"sort"
+	"strings"
 )
 
 type VehicleTypeKey struct {
@@ -58,10 +60,24 @@ func (v *VehicleTypeKey) Equals(other problem.VehicleTypeKey) bool {
 		v.endLocation == other.EndLocation() &&
 		v.earliestStart == other.EarliestStart() &&
 		v.latestEnd == other.LatestEnd() &&
-		v.skills == other.Skills() &&
+		skillsEqual(v.skills, other.Skills()) &&
 		v.returnToDepot == other.ReturnToDepot()
 }
 
+func skillsEqual(s1, s2 *problem.Skills) bool {
+	if s1 == nil || s2 == nil {
+		return s1 == s2
+	}
+	return s1.Equals(s2)
+}
+
+// String identifies the key, i.e. vehicles with equal keys have equal strings. Skills are sorted to make it deterministic.
 func (v *VehicleTypeKey) String() string {
-	return fmt.Sprintf("%s_%s_%s_%.2f_%.2f", v.t, v.startLocation, v.endLocation, v.earliestStart, v.latestEnd)
+	var skills []string
+	if v.skills != nil {
+		skills = v.skills.Values()
+		sort.Strings(skills)
+	}
+	return fmt.Sprintf("%s_%s_%s_%.2f_%.2f_%s_%t", v.t, v.startLocation, v.endLocation, v.earliestStart, v.latestEnd,
+		strings.Join(skills, ","), v.returnToDepot)
 }
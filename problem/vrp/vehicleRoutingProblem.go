@@ -360,6 +360,7 @@ func (b *Builder) Build() *VehicleRoutingProblem {
 	if b.transportCosts == nil {
 		b.transportCosts = cost.NewCrowFlyCosts(b.Locations())
 	}
+	b.checkProfiles()
 
 	for _, job := range b.tentativeJobs {
 		if _, exists := b.jobsInInitialRoutes[job.Id()]; !exists {
@@ -406,6 +407,23 @@ func (b *Builder) Build() *VehicleRoutingProblem {
 	return res
 }
 
+// checkProfiles panics if the transport costs depend on the profile and do not support the profile of a vehicle type.
+func (b *Builder) checkProfiles() {
+	profileCosts, ok := b.transportCosts.(cost.ProfileDependent)
+	if !ok {
+		return
+	}
+	types := b.convertVehicleTypeMapToSlice()
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].TypeId() < types[j].TypeId()
+	})
+	for _, t := range types {
+		if !profileCosts.HasProfile(t.Profile()) {
+			panic(fmt.Sprintf("Transport costs %v do not support profile %s of vehicle type %s.", b.transportCosts, t.Profile(), t.TypeId()))
+		}
+	}
+}
+
 func mergeMaps[M ~map[K]V, K comparable, V any](src ...M) M {
 	merged := make(M)
 	for _, m := range src {
@@ -448,6 +466,19 @@ func (vrp *VehicleRoutingProblem) Jobs() map[string]problem.Job {
 	return copy
 }
 
+// JobsOrderedByIndex returns the jobs, except those fixed in initial routes, ordered by their index. Iterating
+// them instead of Jobs makes an algorithm independent of map order.
+func (vrp *VehicleRoutingProblem) JobsOrderedByIndex() []problem.Job {
+	jobs := make([]problem.Job, 0, len(vrp.jobs))
+	for _, job := range vrp.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Index() < jobs[j].Index()
+	})
+	return jobs
+}
+
 func (vrp *VehicleRoutingProblem) JobsWithLocation() []problem.Job {
 	c := make([]problem.Job, len(vrp.jobsWithLocation))
 	copy(c, vrp.jobsWithLocation)
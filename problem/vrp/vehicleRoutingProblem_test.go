@@ -1,6 +1,7 @@
 package vrp
 
 import (
+	"fmt"
 	"gsprit/problem"
 	"gsprit/problem/cost"
 	"gsprit/problem/driver"
@@ -510,3 +511,53 @@ func TestBuilder_AddingTwoVehiclesDifferentTypeIdentifier_TypeIdentifiersShouldH
 	assert.Equal(t, 1, vehicle1.VehicleTypeIdentifier().Index())
 	assert.Equal(t, 2, vehicle2.VehicleTypeIdentifier().Index())
 }
+
+func TestBuilder_ProfileWithoutTransportCosts_ItShouldThrowException(t *testing.T) {
+	builder := NewBuilder()
+	matrix := cost.NewMatrixTransportCostsBuilder(1, true).Build()
+	builder.SetRoutingCost(cost.NewProfileTransportCostsBuilder("car").AddProfile("car", matrix).Build())
+	hgvType := vehicle.NewVehicleTypeBuilder("hgv").SetProfile("hgv").Build()
+	builder.AddVehicle(vehicle.NewVehicleBuilder("v").SetStartLocation(problem.NewLocationWithIndex(0)).SetType(hgvType).Build())
+
+	assert.PanicsWithValue(t, "Transport costs [name=profileTransportCosts][defaultProfile=car][profiles=car] do not support profile hgv of vehicle type hgv.",
+		func() { builder.Build() })
+}
+
+func TestBuilder_EveryProfileWithTransportCosts_ItShouldBuild(t *testing.T) {
+	builder := NewBuilder()
+	matrix := cost.NewMatrixTransportCostsBuilder(1, true).Build()
+	builder.SetRoutingCost(cost.NewProfileTransportCostsBuilder("car").AddProfile("car", matrix).AddProfile("hgv", matrix).Build())
+	hgvType := vehicle.NewVehicleTypeBuilder("hgv").SetProfile("hgv").Build()
+	builder.AddVehicle(vehicle.NewVehicleBuilder("v1").SetStartLocation(problem.NewLocationWithIndex(0)).SetType(hgvType).Build())
+	builder.AddVehicle(vehicle.NewVehicleBuilder("v2").SetStartLocation(problem.NewLocationWithIndex(0)).Build())
+
+	assert.Len(t, builder.Build().Vehicles(), 2)
+}
+
+func TestBuilder_SettingHaversineCosts_ShouldContainIt(t *testing.T) {
+	vrpBuilder := NewBuilder()
+	vrpBuilder.SetRoutingCost(cost.NewHaversineCosts())
+	berlin := problem.NewLocationWithCoordinate(13.4050, 52.5200)
+	paris := problem.NewLocationWithCoordinate(2.3522, 48.8566)
+	vrp := vrpBuilder.Build()
+
+	assert.Equal(t, cost.HaversineDistance(berlin.Coordinate(), paris.Coordinate()), vrp.TransportCosts().Distance(berlin, paris, 0.0, nil))
+}
+
+func TestBuilder_JobsOrderedByIndex_ShouldReturnJobsInOrderOfIndex(t *testing.T) {
+	builder := NewBuilder()
+	jobs := make([]problem.Job, 0)
+	for i := 0; i < 10; i++ {
+		s := job.NewServiceBuilder[*job.Service](fmt.Sprintf("s%d", i)).SetLocation(problem.NewLocationWithID("loc")).Build()
+		jobs = append(jobs, s)
+		builder.AddJob(s)
+	}
+	vrp := builder.Build()
+
+	ordered := vrp.JobsOrderedByIndex()
+
+	assert.ElementsMatch(t, jobs, ordered)
+	for i, j := range ordered {
+		assert.Equal(t, i+1, j.Index())
+	}
+}
@@ -0,0 +1,43 @@
+package util
+
+import "math/rand/v2"
+
+// DefaultSeed seeds every random number generator that is not injected explicitly.
+const DefaultSeed uint64 = 4711
+
+// NewRandom creates a deterministic random number generator from seed.
+func NewRandom(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// NewRandomStream creates the generator of the given stream derived from seed. Generators of different streams
+// are independent of each other, e.g. one per worker of a parallel search.
+func NewRandomStream(seed, stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, splitMix64(seed^splitMix64(stream+1))))
+}
+
+// splitMix64 scrambles x with the finalizer of the SplitMix64 generator.
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// RandomSource derives independent generators from a single seed, one stream after another. Components that
+// take their generators from the same source in the same order get the same generators.
+type RandomSource struct {
+	seed   uint64
+	stream uint64
+}
+
+func NewRandomSource(seed uint64) *RandomSource {
+	return &RandomSource{seed: seed}
+}
+
+// Next returns the generator of the next stream.
+func (s *RandomSource) Next() *rand.Rand {
+	r := NewRandomStream(s.seed, s.stream)
+	s.stream++
+	return r
+}